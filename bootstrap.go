@@ -0,0 +1,130 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A MissingParameter is a parameter read by a config that does not exist in
+// SSM. It is returned by Bootstrap.
+type MissingParameter struct {
+	// Name is the name of the parameter.
+	Name string `json:"name"`
+	// Field is the path of the field the parameter is read into, such as
+	// "DB.Password". If the parameter is read into several fields, this is
+	// the first one declared.
+	Field string `json:"field"`
+	// Type is the parameter type to create: SecureString for fields with the
+	// secure tag option, StringList for slices, otherwise String.
+	Type string `json:"type"`
+	// Default is the value of the default= tag option, if set. Read uses it
+	// while the parameter does not exist.
+	Default string `json:"default,omitempty"`
+	// Description is the description from the desc= tag option, if set.
+	Description string `json:"description,omitempty"`
+}
+
+// Bootstrap compares the parameters read into target against SSM and returns
+// the parameters that need to be created, in the order the fields are
+// declared. This is intended for onboarding new environments; the result can
+// be encoded as JSON for other tools.
+//
+// target is not modified.
+func (s *ParamStore) Bootstrap(ctx context.Context, target interface{}) ([]MissingParameter, error) {
+	val, err := targetValue(target)
+	if err != nil {
+		return nil, err
+	}
+	ty := val.Type()
+	schema, err := s.schema(ty, s.prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := s.fetch(ctx, sortedNames(schema))
+	if err != nil {
+		return nil, fmt.Errorf("read ssm: %v", err)
+	}
+	found := byName(params)
+
+	var missing []MissingParameter
+	for _, name := range declOrder(schema) {
+		if _, ok := found[name]; ok || isPath(name) {
+			// The parameters of map fields are not known
+			continue
+		}
+		if _, selector := splitSelector(name); selector != "" {
+			// A specific version can not be created
+			continue
+		}
+		indices := schema[name]
+		p := MissingParameter{
+			Name:  name,
+			Field: strings.Join(fieldPath(ty, indices[0]), "."),
+			Type:  string(paramType(structField(ty, indices[0]).Type)),
+		}
+		if _, ok := s.fieldOption(ty, indices, "sep"); ok || s.fieldFlag(ty, indices, "json") || s.fieldFlag(ty, indices, "kv") {
+			p.Type = string(types.ParameterTypeString)
+		}
+		if s.fieldFlag(ty, indices, "secure") {
+			p.Type = string(types.ParameterTypeSecureString)
+		}
+		p.Default, _ = s.fieldOption(ty, indices, "default")
+		p.Description, _ = s.fieldOption(ty, indices, "desc")
+		missing = append(missing, p)
+	}
+	return missing, nil
+}
+
+// BootstrapApply creates the parameters reported by Bootstrap that have a
+// default= tag option, using the default as value. Fields with the secure tag
+// option are created as SecureString, encrypted with the key set by
+// WithSecureString, or the default key if it was not passed:
+//
+//   type Config struct {
+//       Port     string `ssm:"port,default=5432"`
+//       Password string `ssm:"password,secure,default=changeme"`
+//       Host     string `ssm:"host"`
+//   }
+//
+// The parameters that are still missing, as they have no default, are
+// returned. SSM does not allow empty values, so an empty default is treated as
+// no default. Existing parameters are never modified.
+func (s *ParamStore) BootstrapApply(ctx context.Context, target interface{}, opts ...WriteOption) ([]MissingParameter, error) {
+	missing, err := s.Bootstrap(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.overwrite = false
+
+	var remaining []MissingParameter
+	for _, p := range missing {
+		if p.Default == "" {
+			remaining = append(remaining, p)
+			continue
+		}
+		if err := s.put(ctx, p.Name, p.Default, types.ParameterType(p.Type), o); err != nil {
+			return nil, fmt.Errorf("create %s: %v", p.Name, err)
+		}
+	}
+	return remaining, nil
+}
+
+// paramType returns the parameter type to store values of type t in.
+func paramType(t reflect.Type) types.ParameterType {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && !isText(t) && !isJSON(t) {
+		return types.ParameterTypeStringList
+	}
+	return types.ParameterTypeString
+}
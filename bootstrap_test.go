@@ -0,0 +1,115 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParamStore_Bootstrap(t *testing.T) {
+	type config struct {
+		Host  string   `ssm:"host"`
+		Port  string   `ssm:"port,default=5432,desc=Database port"`
+		Peers []string `ssm:"peers"`
+		DB    struct {
+			User string `ssm:"user"`
+		} `ssm:"db"`
+	}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/dev/host", "localhost"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithPrefix("dev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config
+	got, err := ps.Bootstrap(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MissingParameter{
+		{Name: "/dev/port", Field: "Port", Type: "String", Default: "5432", Description: "Database port"},
+		{Name: "/dev/peers", Field: "Peers", Type: "StringList"},
+		{Name: "/dev/db/user", Field: "DB.User", Type: "String"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Bootstrap() (-got +want)\n%s", diff)
+	}
+	if cfg.Host != "" {
+		t.Errorf("Target was modified")
+	}
+}
+
+func TestParamStore_Bootstrap_complete(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "localhost"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+	}
+	got, err := ps.Bootstrap(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Bootstrap() = %v, want none", got)
+	}
+}
+
+func TestParamStore_BootstrapApply(t *testing.T) {
+	type config struct {
+		Host     string   `ssm:"host"`
+		Port     string   `ssm:"port,default=5432"`
+		Password string   `ssm:"password,secure,default=changeme"`
+		Peers    []string `ssm:"peers,default='a,b'"`
+		Existing string   `ssm:"existing,default=new"`
+	}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/existing", "old"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remaining, err := ps.BootstrapApply(context.Background(), &config{}, WithSecureString("alias/config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MissingParameter{
+		{Name: "/host", Field: "Host", Type: "String"},
+	}
+	if diff := cmp.Diff(remaining, want); diff != "" {
+		t.Errorf("BootstrapApply() (-got +want)\n%s", diff)
+	}
+
+	got := make(map[string]string)
+	for _, p := range mock.getParams() {
+		got[*p.Name] = string(p.Type) + " " + *p.Value
+	}
+	wantParams := map[string]string{
+		"/existing": "String old",
+		"/port":     "String 5432",
+		"/password": "SecureString changeme",
+		"/peers":    "StringList a,b",
+	}
+	if diff := cmp.Diff(got, wantParams); diff != "" {
+		t.Errorf("Parameters (-got +want)\n%s", diff)
+	}
+	if key := mock.keyIDs["/password"]; key != "alias/config" {
+		t.Errorf("KeyId = %q, want %q", key, "alias/config")
+	}
+	if _, ok := mock.keyIDs["/port"]; ok {
+		t.Errorf("KeyId set for String parameter")
+	}
+}
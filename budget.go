@@ -0,0 +1,122 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WithReadBudget limits the time Read spends fetching parameters to d. If the
+// budget is exhausted before every parameter was fetched, the fields of the
+// parameters that were fetched are set, and a PartialError listing the other
+// fields is returned:
+//
+//   err := ps.Read(ctx, &cfg)
+//   var perr ssm.PartialError
+//   if errors.As(err, &perr) {
+//       // cfg is set, except for the fields of perr.Fields()
+//   }
+//
+// This allows latency-critical services to proceed with a partial config
+// instead of failing. Parameters are fetched in batches; a batch that did
+// not complete within the budget is not read. Other errors fail the read as
+// usual. The initial reads of Watch, Updates and StartRefresh are limited by
+// the budget, polling and refreshing the config afterwards are not.
+func WithReadBudget(d time.Duration) Option {
+	return func(s *ParamStore) {
+		s.readBudget = d
+	}
+}
+
+// errBudgetExceeded is the cause of the context of a read that ran out of
+// its budget.
+var errBudgetExceeded = errors.New("read budget exceeded")
+
+type budgetKey struct{}
+
+// withBudget returns a context carrying the deadline of the read budget of s,
+// if set. The budget is applied by fetchShared to the fetch, which may be
+// shared with other reads, rather than to ctx.
+func (s *ParamStore) withBudget(ctx context.Context) context.Context {
+	if s.readBudget <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, budgetKey{}, time.Now().Add(s.readBudget))
+}
+
+// budgetContext returns ctx limited by the read budget carried by from, if
+// any. The returned context is done with errBudgetExceeded as cause when the
+// budget is exhausted.
+func budgetContext(ctx, from context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := from.Value(budgetKey{}).(time.Time)
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadlineCause(ctx, deadline, errBudgetExceeded)
+}
+
+// budgetExceeded reports whether ctx is done because the read budget was
+// exhausted.
+func budgetExceeded(ctx context.Context) bool {
+	return context.Cause(ctx) == errBudgetExceeded
+}
+
+// A PartialError is returned with WithReadBudget when the budget was
+// exhausted before every parameter was fetched. The fields of the parameters
+// that were fetched are set.
+type PartialError struct {
+	names  []string
+	fields []string
+}
+
+func (e PartialError) Error() string {
+	unread := e.fields
+	if len(unread) == 0 {
+		unread = e.names
+	}
+	return fmt.Sprintf("%v, not read: %s", errBudgetExceeded, strings.Join(unread, ", "))
+}
+
+// Names returns the names of the parameters that were not fetched.
+func (e PartialError) Names() []string {
+	return e.names
+}
+
+// Fields returns the fields that were not set, such as DB.Host.
+func (e PartialError) Fields() []string {
+	return e.fields
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e PartialError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// withFields returns e with the fields of its names in the schema of ty.
+func (e PartialError) withFields(ty reflect.Type, schema map[string][][]int) PartialError {
+	e.fields = nil
+	for _, name := range e.names {
+		for _, index := range schema[name] {
+			e.fields = append(e.fields, strings.Join(fieldPath(ty, index), "."))
+		}
+	}
+	sort.Strings(e.fields)
+	return e
+}
+
+// unreadNames returns the names of the parameters that could not be read if
+// err is a DecryptionError or a PartialError, in which case the other
+// parameters were read.
+func unreadNames(err error) ([]string, bool) {
+	switch err := err.(type) {
+	case DecryptionError:
+		return err.names, true
+	case PartialError:
+		return err.names, true
+	}
+	return nil, false
+}
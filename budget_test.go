@@ -0,0 +1,61 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParamStore_Read_budget(t *testing.T) {
+	ty, params, _ := largeConfig(25)
+	mock := &mockSSM{params: params, delay: 100 * time.Millisecond}
+	ps, err := NewParamStore(WithClient(mock), WithReadBudget(150*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	val := reflect.New(ty)
+	err = ps.Read(context.Background(), val.Interface())
+	var perr PartialError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Read() err = %v, want PartialError", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Want error to be context.DeadlineExceeded")
+	}
+	// The first batch of 10 completes within the budget.
+	if got := len(perr.Names()); got != 15 {
+		t.Errorf("Names() has %d names, want 15", got)
+	}
+	unread := make(map[string]bool)
+	for _, f := range perr.Fields() {
+		unread[f] = true
+	}
+	if len(unread) != 15 {
+		t.Errorf("Fields() has %d fields, want 15", len(unread))
+	}
+	for i := 0; i < ty.NumField(); i++ {
+		name := ty.Field(i).Name
+		set := val.Elem().Field(i).String() != ""
+		if set == unread[name] {
+			t.Errorf("%s set = %t, unread = %t", name, set, unread[name])
+		}
+	}
+}
+
+func TestParamStore_Read_budgetComplete(t *testing.T) {
+	ty, params, want := largeConfig(25)
+	mock := &mockSSM{params: params, delay: 10 * time.Millisecond}
+	ps, err := NewParamStore(WithClient(mock), WithReadBudget(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	val := reflect.New(ty)
+	if err := ps.Read(context.Background(), val.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	check(t, val.Elem().Interface(), want)
+}
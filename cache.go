@@ -0,0 +1,340 @@
+package ssm
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A Cache stores parameters between reads. Implementations must be safe for
+// concurrent use.
+//
+// The rediscache and dynamocache modules provide caches that can be shared by
+// multiple processes. The diskcache subpackage stores parameters in an
+// encrypted file.
+type Cache interface {
+	// Get returns the cached parameter with the given name. If the parameter
+	// is not cached or has expired, false is returned.
+	Get(ctx context.Context, name string) (CachedParameter, bool, error)
+	// Set stores the parameter for the duration of ttl.
+	Set(ctx context.Context, param CachedParameter, ttl time.Duration) error
+	// Delete removes the parameter with the given name from the cache.
+	Delete(ctx context.Context, name string) error
+}
+
+// A CachedParameter is a parameter stored in a Cache.
+//
+// Values of SecureString parameters are stored decrypted. Caches shared
+// between processes must be secured accordingly. Parameters read with the
+// nodecrypt tag option are stored encrypted, with "#encrypted" appended to
+// the name.
+type CachedParameter struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	Version int64  `json:"version"`
+}
+
+// WithSharedCache caches parameters in c for the duration of ttl. Reads use
+// cached parameters when available and only request the remaining parameters
+// from SSM.
+//
+// If the cache returns an error, the parameters are read from SSM instead.
+func WithSharedCache(c Cache, ttl time.Duration) Option {
+	return func(s *ParamStore) {
+		s.cache = c
+		s.cacheTTL = ttl
+	}
+}
+
+// WithCache caches parameters in memory for the duration of ttl. Reads only
+// request parameters from SSM that are not cached or have expired.
+//
+// Pass a context created with BypassCache to Read to always request the
+// parameters from SSM.
+func WithCache(ttl time.Duration) Option {
+	return func(s *ParamStore) {
+		s.cache = &memoryCache{
+			entries: make(map[string]memoryEntry),
+			now:     func() time.Time { return s.now() },
+		}
+		s.cacheTTL = ttl
+	}
+}
+
+// NewMemoryCache returns a Cache that stores parameters in memory. Unlike
+// WithCache, the cache can be passed to WithSharedCache of several
+// ParamStores, for example with different prefixes, so parameters read by one
+// are not requested again by the others:
+//
+//   c := ssm.NewMemoryCache()
+//   app, err := ssm.NewParamStore(ssm.WithPrefix("prod/app"), ssm.WithSharedCache(c, time.Minute))
+//   db, err := ssm.NewParamStore(ssm.WithPrefix("prod/db"), ssm.WithSharedCache(c, time.Minute))
+func NewMemoryCache() Cache {
+	return &memoryCache{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a context that makes reads request parameters from SSM
+// instead of using cached values. The parameters read are still added to the
+// cache.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func isBypassCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// memoryCache is a Cache that stores parameters in memory.
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	param   CachedParameter
+	expires time.Time
+}
+
+func (c *memoryCache) Get(ctx context.Context, name string) (CachedParameter, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[name]
+	if !ok {
+		return CachedParameter{}, false, nil
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, name)
+		return CachedParameter{}, false, nil
+	}
+	return e.param, true, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, param CachedParameter, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[param.Name] = memoryEntry{
+		param:   param,
+		expires: c.now().Add(ttl),
+	}
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, name)
+	return nil
+}
+
+// WithNegativeCache remembers parameters that were not found for the duration
+// of ttl. They are not requested again until ttl has passed, and are reported
+// as not found.
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(s *ParamStore) {
+		s.notFoundTTL = ttl
+	}
+}
+
+// forget removes the parameters from the cache, the negative cache and the
+// stale values, so they are requested from SSM on the next read. Every version
+// of a parameter is removed: the one with the label set with WithLabel, and
+// those read with a version or label selector.
+func (s *ParamStore) forget(ctx context.Context, names []string) {
+	s.mu.Lock()
+	var keys []string
+	for _, name := range names {
+		keys = append(keys, s.selectedNames(name)...)
+	}
+	for _, name := range keys {
+		delete(s.notFound, name)
+		delete(s.stale, name)
+		delete(s.stale, name+encryptedSuffix)
+	}
+	s.mu.Unlock()
+	if s.cache == nil {
+		return
+	}
+	for _, name := range keys {
+		for _, key := range []string{name, name + encryptedSuffix} {
+			if err := s.cache.Delete(ctx, key); err != nil {
+				s.log(ctx, slog.LevelWarn, "delete cached parameter",
+					slog.String("name", key),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+}
+
+// selectedNames returns the name and the names of the versions of the
+// parameter that may be cached. s.mu must be held.
+func (s *ParamStore) selectedNames(name string) []string {
+	base, selector := splitSelector(name)
+	names := []string{name}
+	if selector != "" {
+		names = append(names, base)
+	}
+	if s.label != "" && selector != ":"+s.label {
+		names = append(names, base+":"+s.label)
+	}
+	for selector := range s.selectors[base] {
+		if base+selector != name && selector != ":"+s.label {
+			names = append(names, base+selector)
+		}
+	}
+	return names
+}
+
+// recordSelectors remembers the selectors the parameters are read with.
+func (s *ParamStore) recordSelectors(names []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		base, selector := splitSelector(name)
+		if selector == "" {
+			continue
+		}
+		if s.selectors[base] == nil {
+			s.selectors[base] = make(map[string]bool)
+		}
+		s.selectors[base][selector] = true
+	}
+}
+
+// fetchCached gets the parameters with the given names, from the cache if
+// set, otherwise from SSM. Parameters read from SSM are added to the cache.
+func (s *ParamStore) fetchCached(ctx context.Context, names []string) ([]types.Parameter, error) {
+	// The paths of map fields are not cached, as the parameters under them
+	// are not known in advance.
+	paths, names := splitPaths(names)
+	s.recordSelectors(names)
+	bypass := isBypassCache(ctx)
+	if !bypass {
+		names = s.skipNotFound(names)
+	}
+
+	var (
+		params  []types.Parameter
+		missing []string
+	)
+	if s.cache == nil || bypass {
+		missing = names
+	}
+	for _, name := range names {
+		if s.cache == nil || bypass {
+			break
+		}
+		cp, ok, err := s.cache.Get(ctx, s.cacheKey(ctx, name))
+		if err != nil {
+			s.log(ctx, slog.LevelWarn, "get cached parameter",
+				slog.String("name", name),
+				slog.String("error", err.Error()),
+			)
+		}
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		recordSource(ctx, SourceCache, name)
+		cp.Name = name
+		params = append(params, fromCached(cp))
+	}
+	if len(missing) == 0 && len(paths) == 0 {
+		return params, nil
+	}
+
+	fetched, err := s.fetch(ctx, append(missing, paths...))
+	unread, partial := unreadNames(err)
+	if err != nil && !partial {
+		return nil, err
+	}
+	if partial {
+		// The parameters that could not be decrypted or fetched within the
+		// read budget are not missing
+		missing = without(missing, unread)
+	}
+	s.recordNotFound(missing, fetched)
+	recordSource(ctx, SourceSSM, append(missing, paths...)...)
+	if s.cache == nil {
+		return fetched, err
+	}
+	for _, p := range fetched {
+		cp := toCached(p)
+		cp.Name = s.cacheKey(ctx, *p.Name)
+		if err := s.cache.Set(ctx, cp, s.ttl(ctx, *p.Name)); err != nil {
+			s.log(ctx, slog.LevelWarn, "cache parameter",
+				slog.String("name", *p.Name),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	return append(params, fetched...), err
+}
+
+// skipNotFound returns the names that are not known to be missing.
+func (s *ParamStore) skipNotFound(names []string) []string {
+	if s.notFoundTTL <= 0 {
+		return names
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if exp, ok := s.notFound[name]; ok && now.Before(exp) {
+			continue
+		}
+		delete(s.notFound, name)
+		out = append(out, name)
+	}
+	return out
+}
+
+// recordNotFound remembers the requested names that were not fetched.
+func (s *ParamStore) recordNotFound(requested []string, fetched []types.Parameter) {
+	if s.notFoundTTL <= 0 {
+		return
+	}
+	found := make(map[string]bool, len(fetched))
+	for _, p := range fetched {
+		found[*p.Name] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	exp := s.now().Add(s.notFoundTTL)
+	for _, name := range requested {
+		if !found[name] {
+			s.notFound[name] = exp
+		}
+	}
+}
+
+func toCached(p types.Parameter) CachedParameter {
+	return CachedParameter{
+		Name:    aws.ToString(p.Name),
+		Type:    string(p.Type),
+		Value:   aws.ToString(p.Value),
+		Version: p.Version,
+	}
+}
+
+func fromCached(cp CachedParameter) types.Parameter {
+	return types.Parameter{
+		Name:    aws.String(cp.Name),
+		Type:    types.ParameterType(cp.Type),
+		Value:   aws.String(cp.Value),
+		Version: cp.Version,
+	}
+}
@@ -0,0 +1,242 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParamStore_Read_sharedCache(t *testing.T) {
+	type config struct {
+		A string `ssm:"a"`
+		B string `ssm:"b"`
+	}
+
+	cache := newTestCache()
+	cache.entries["/a"] = CachedParameter{Name: "/a", Type: "String", Value: "cached"}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+		secureStringParam("/b", "2"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithSharedCache(cache, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "A", value: "cached"},
+		{path: "B", value: "2"},
+	})
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+	want := map[string]CachedParameter{
+		"/a": {Name: "/a", Type: "String", Value: "cached"},
+		"/b": {Name: "/b", Type: "SecureString", Value: "2"},
+	}
+	if diff := cmp.Diff(cache.entries, want); diff != "" {
+		t.Errorf("Cache (-got +want)\n%s", diff)
+	}
+	if cache.ttl != time.Minute {
+		t.Errorf("TTL = %s, want %s", cache.ttl, time.Minute)
+	}
+
+	// Everything cached
+	cfg = config{}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "A", value: "cached"},
+		{path: "B", value: "2"},
+	})
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+}
+
+func TestParamStore_Read_sharedCacheError(t *testing.T) {
+	cache := newTestCache()
+	cache.err = fmt.Errorf("cache unavailable")
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithSharedCache(cache, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "1"}})
+}
+
+// testCache is a Cache that never expires.
+type testCache struct {
+	mu      sync.Mutex
+	entries map[string]CachedParameter
+	ttl     time.Duration
+	err     error
+}
+
+func newTestCache() *testCache {
+	return &testCache{entries: make(map[string]CachedParameter)}
+}
+
+func (c *testCache) Get(ctx context.Context, name string) (CachedParameter, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return CachedParameter{}, false, c.err
+	}
+	cp, ok := c.entries[name]
+	return cp, ok, nil
+}
+
+func (c *testCache) Set(ctx context.Context, param CachedParameter, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.entries[param.Name] = param
+	c.ttl = ttl
+	return nil
+}
+
+func (c *testCache) Delete(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	delete(c.entries, name)
+	return nil
+}
+
+func TestParamStore_Read_negativeCache(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithNegativeCache(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.now = func() time.Time { return now }
+
+	var cfg struct {
+		B string `ssm:"b"`
+	}
+	for i := 0; i < 3; i++ {
+		err := ps.Read(context.Background(), &cfg)
+		if _, ok := err.(NotFoundError); !ok {
+			t.Fatalf("Read() err = %v, want NotFoundError", err)
+		}
+	}
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+
+	// Created after the first read, found after the TTL has passed
+	mock.params = append(mock.params, stringParam("/b", "2"))
+	now = now.Add(time.Minute)
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "B", value: "2"}})
+	if mock.calls != 2 {
+		t.Errorf("GetParameters called %d times, want 2", mock.calls)
+	}
+}
+
+func TestParamStore_Read_cache(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.now = func() time.Time { return now }
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	read := func(ctx context.Context, want string, wantCalls int) {
+		t.Helper()
+		if err := ps.Read(ctx, &cfg); err != nil {
+			t.Fatal(err)
+		}
+		check(t, cfg, []value{{path: "A", value: want}})
+		if mock.calls != wantCalls {
+			t.Errorf("GetParameters called %d times, want %d", mock.calls, wantCalls)
+		}
+	}
+
+	read(context.Background(), "1", 1)
+	mock.setParam(stringParam("/a", "2"))
+	read(context.Background(), "1", 1)
+
+	// Bypass reads from SSM and updates the cache
+	read(BypassCache(context.Background()), "2", 2)
+	read(context.Background(), "2", 2)
+
+	// Expired
+	mock.setParam(stringParam("/a", "3"))
+	now = now.Add(time.Minute)
+	read(context.Background(), "3", 3)
+}
+
+func TestNewMemoryCache(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/prod/app/name", "app"),
+	}}
+	c := NewMemoryCache()
+	newStore := func(prefix string) *ParamStore {
+		t.Helper()
+		ps, err := NewParamStore(WithClient(mock), WithPrefix(prefix), WithSharedCache(c, time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ps
+	}
+
+	var all struct {
+		App struct {
+			Name string `ssm:"name"`
+		} `ssm:"app"`
+	}
+	if err := newStore("prod").Read(context.Background(), &all); err != nil {
+		t.Fatal(err)
+	}
+
+	// Read by another ParamStore from the cache
+	mock.setParam(stringParam("/prod/app/name", "changed"))
+	var app struct {
+		Name string `ssm:"name"`
+	}
+	if err := newStore("prod/app").Read(context.Background(), &app); err != nil {
+		t.Fatal(err)
+	}
+	check(t, app, []value{{path: "Name", value: "app"}})
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+}
@@ -0,0 +1,84 @@
+// Command ssm-reconcile keeps a local file in sync with the parameters under a
+// prefix in AWS Systems Manager Parameter Store, so processes on the host that
+// cannot read from SSM always have the current config.
+//
+// Usage:
+//
+//   ssm-reconcile -prefix /prod/app -out /etc/app/config.env [flags]
+//
+// The file is written as json if it ends in .json, and as VAR=value lines
+// otherwise; -format overrides this. It is only rewritten when a parameter
+// changes, atomically, after which the -notify command is run:
+//
+//   ssm-reconcile -prefix /prod/app -out /etc/app/config.env \
+//       -notify 'systemctl reload app'
+//
+// The AWS config is loaded from the environment in the usual way.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/akupila/ssm"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "ssm-reconcile: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		prefix   = flag.String("prefix", "", "`path` of the parameters to write (required)")
+		out      = flag.String("out", "", "`file` to write (required)")
+		format   = flag.String("format", "", "file format, json or env (default from the file extension)")
+		interval = flag.Duration("interval", ssm.DefaultPollInterval, "interval at which parameters are read")
+		notify   = flag.String("notify", "", "shell `command` to run after the file is written")
+	)
+	flag.Parse()
+	if *prefix == "" || *out == "" {
+		flag.Usage()
+		return fmt.Errorf("-prefix and -out are required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	ps, err := ssm.NewParamStore(
+		ssm.WithPollInterval(*interval),
+		ssm.WithLogger(logger),
+		ssm.WithAppName("ssm-reconcile"),
+	)
+	if err != nil {
+		return err
+	}
+
+	opts := []ssm.ReconcileOption{ssm.WithFileFormat(*format)}
+	if *notify != "" {
+		opts = append(opts, ssm.WithNotify(func(path string) {
+			cmd := exec.CommandContext(ctx, "/bin/sh", "-c", *notify)
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			cmd.Env = append(os.Environ(), "SSM_RECONCILE_FILE="+path)
+			if err := cmd.Run(); err != nil {
+				logger.Warn("notify command failed", slog.String("error", err.Error()))
+			}
+		}))
+	}
+
+	err = ps.Reconcile(ctx, *prefix, *out, opts...)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
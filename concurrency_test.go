@@ -0,0 +1,224 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// The tests in this file read from many goroutines at once, and are mainly
+// useful with the race detector:
+//
+//   go test -race -run Concurrent
+
+type concurrentConfig struct {
+	Host     string            `ssm:"host"`
+	Port     int               `ssm:"port,ttl=1ms"`
+	Password string            `ssm:"password,secure"`
+	Timeout  time.Duration     `ssm:"timeout,default=5s"`
+	Old      string            `ssm:"old,optional,deprecated"`
+	Hosts    []string          `ssm:"hosts"`
+	Limits   map[string]string `ssm:"limits"`
+	Features map[string]bool   `ssm:"features,json"`
+	DB       struct {
+		User string `ssm:"user"`
+	} `ssm:"db"`
+}
+
+func concurrentParams() []types.Parameter {
+	return []types.Parameter{
+		stringParam("/app/host", "localhost"),
+		stringParam("/app/port", "5432"),
+		secureStringParam("/app/password", "secret"),
+		stringParam("/app/old", "legacy"),
+		stringListParam("/app/hosts", "a,b"),
+		stringParam("/app/limits/users", "10"),
+		stringParam("/app/limits/teams", "2"),
+		stringParam("/app/features", `{"beta":true}`),
+		stringParam("/app/db/user", "admin"),
+	}
+}
+
+func TestParamStore_Read_concurrentOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{name: "Default"},
+		{name: "Cache", options: []Option{WithCache(time.Millisecond)}},
+		{name: "SharedCache", options: []Option{WithSharedCache(NewMemoryCache(), time.Millisecond)}},
+		{name: "Stale", options: []Option{WithCache(time.Millisecond), WithStaleWhileRevalidate(nil)}},
+		{name: "NegativeCache", options: []Option{WithNegativeCache(time.Millisecond)}},
+		{name: "PathFetch", options: []Option{WithPathFetch()}},
+		{name: "Retry", options: []Option{WithRetry(3, time.Millisecond), WithRateLimit(1e6, 100)}},
+		{name: "Scheduler", options: []Option{WithScheduler(NewScheduler(1e6, 100, 4)), WithMaxConcurrency(4)}},
+		{name: "OnChange", options: []Option{WithOnChange("host", func(old, new string) {})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSSM{params: concurrentParams()}
+			options := append([]Option{
+				WithClient(mock),
+				WithPrefix("app"),
+				WithParseDuration(),
+				WithParseNumber(),
+				WithConverter(reflect.TypeOf(false), func(v string) (interface{}, error) {
+					return strconv.ParseBool(v)
+				}),
+				WithJournal(NewJournal(filepath.Join(t.TempDir(), "journal"))),
+			}, tt.options...)
+			ps, err := NewParamStore(options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 10; j++ {
+						var cfg concurrentConfig
+						var err error
+						switch (i + j) % 4 {
+						case 0:
+							err = ps.Read(ctx, &cfg)
+						case 1:
+							_, err = ps.ReadWithReport(ctx, &cfg)
+						case 2:
+							_, err = ps.ReadWithSummary(ctx, &cfg)
+						case 3:
+							err = ps.Read(BypassCache(ctx), &cfg)
+							ps.Invalidate(ctx, "/app/host")
+						}
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						if cfg.Host == "" || cfg.Port != 5432 || cfg.Limits["users"] != "10" || !cfg.Features["beta"] {
+							t.Errorf("Read() = %+v", cfg)
+						}
+						ps.DumpRedacted(&cfg)
+						if j == 5 {
+							mock.setParam(stringParam("/app/host", fmt.Sprintf("host-%d", i)))
+						}
+					}
+				}(i)
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestParamStore_concurrentWatchers(t *testing.T) {
+	mock := &mockSSM{params: concurrentParams()}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPrefix("app"),
+		WithParseDuration(),
+		WithParseNumber(),
+		WithCache(time.Millisecond),
+		WithPollInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := ps.StartRefresh(ctx, &concurrentConfig{}, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+	updates, err := ps.Updates(ctx, &concurrentConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := ps.Watch(ctx, &concurrentConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := ps.SubscribeValue(ctx, "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for range updates {
+		}
+	}()
+	go func() {
+		for range changes {
+		}
+	}()
+	go func() {
+		for range values {
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				mock.setParam(stringParam("/app/host", fmt.Sprintf("host-%d-%d", i, j)))
+				var cfg concurrentConfig
+				if err := ps.Read(ctx, &cfg); err != nil {
+					t.Error(err)
+					return
+				}
+				if cfg := r.Current().(*concurrentConfig); cfg.Host == "" {
+					t.Error("Current() has no host")
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestTenantStore_Read_concurrent(t *testing.T) {
+	mock := &mockSSM{}
+	tenants := []string{"a", "b", "c"}
+	for _, tenant := range tenants {
+		for _, p := range concurrentParams() {
+			p.Name = aws.String("/tenants/" + tenant + *p.Name)
+			mock.setParam(p)
+		}
+	}
+	ts, err := NewTenantStore("/tenants/{tenant}/app", WithClient(mock), WithParseDuration(), WithParseNumber(), WithCache(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tenant := tenants[i%len(tenants)]
+			for j := 0; j < 10; j++ {
+				var cfg concurrentConfig
+				if err := ts.Read(ctx, tenant, &cfg); err != nil {
+					t.Error(err)
+					return
+				}
+				if j == 5 {
+					ts.Invalidate(ctx, tenant)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
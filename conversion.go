@@ -0,0 +1,118 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// WithConversionReport records how the value of each field was converted from
+// the parameter value in the Report returned by ReadWithReport, so it can be
+// verified how the values in SSM became the typed config.
+func WithConversionReport() Option {
+	return func(s *ParamStore) {
+		s.conversionReport = true
+	}
+}
+
+// A Conversion describes how the value of a parameter was converted to the
+// type of a field.
+type Conversion struct {
+	// From is the type of the parameter, such as StringList.
+	From string
+	// To is the type of the field, such as []int.
+	To string
+	// Steps are the conversions made, in order, such as
+	// `split by ","` and "parse int".
+	Steps []string
+}
+
+func (c Conversion) String() string {
+	return fmt.Sprintf("%s to %s: %s", c.From, c.To, strings.Join(c.Steps, ", "))
+}
+
+// recordConversion records the conversion of the parameter to the field at
+// index, if ctx was created by ReadWithReport. Values that are set as is are
+// not recorded.
+func (s *ParamStore) recordConversion(ctx context.Context, p types.Parameter, ty reflect.Type, index []int, opts tagOptions) {
+	r, ok := ctx.Value(recorderKey{}).(*sourceRecorder)
+	if !ok {
+		return
+	}
+	to := structField(ty, index).Type
+	sep, _ := opts.Get("sep")
+	steps := s.conversionSteps(p.Type, to, sep)
+	if opts.Contains("json") {
+		steps = []string{"decode json"}
+	}
+	if opts.Contains("kv") {
+		m := to
+		for m.Kind() == reflect.Ptr {
+			m = m.Elem()
+		}
+		steps = append([]string{"split key=value pairs"}, s.conversionSteps(types.ParameterTypeString, m.Elem(), "")...)
+	}
+	if len(steps) == 0 {
+		return
+	}
+	field := strings.Join(fieldPath(ty, index), ".")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conversions[field] = &Conversion{
+		From:  string(p.Type),
+		To:    to.String(),
+		Steps: steps,
+	}
+}
+
+// conversionSteps describes the conversion of a parameter of type from to a
+// value of type to.
+func (s *ParamStore) conversionSteps(from types.ParameterType, to reflect.Type, sep string) []string {
+	for to.Kind() == reflect.Ptr {
+		to = to.Elem()
+	}
+	if sep != "" && to.Kind() == reflect.Slice {
+		return append([]string{fmt.Sprintf("split by %q", sep)}, s.conversionSteps(types.ParameterTypeString, to.Elem(), "")...)
+	}
+	if from == types.ParameterTypeStringList && to.Kind() == reflect.Slice && !s.types[to] && !isText(to) && !isJSON(to) {
+		return append([]string{`split by ","`}, s.conversionSteps(types.ParameterTypeString, to.Elem(), "")...)
+	}
+
+	switch {
+	case s.types[to]:
+		return []string{"converter for " + to.String()}
+	case to == reflect.TypeOf(time.Duration(0)):
+		return []string{"parse duration"}
+	case to == reflect.TypeOf(time.Time{}):
+		return []string{"parse time"}
+	case nullTypes[to]:
+		return []string{"parse " + to.String()}
+	case to == urlType:
+		return []string{"parse url"}
+	case to == ipNetType:
+		return []string{"parse cidr"}
+	case to == bigFloatType:
+		return []string{"parse big.Float"}
+	case isText(to):
+		return []string{"unmarshal text"}
+	case isJSON(to):
+		return []string{"unmarshal json"}
+	case reflect.PtrTo(to).Implements(binaryUnmarshalerType):
+		return []string{"decode base64"}
+	}
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []string{"parse int"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return []string{"parse uint"}
+	case reflect.Float32, reflect.Float64:
+		return []string{"parse float"}
+	case reflect.Bool:
+		return []string{"parse bool"}
+	}
+	return nil
+}
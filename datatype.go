@@ -0,0 +1,51 @@
+package ssm
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// Data types of String parameters. Parameters without a data type are text.
+const (
+	dataTypeText        = "text"
+	dataTypeImage       = "aws:ec2:image"
+	dataTypeIntegration = "aws:ssm:integration"
+)
+
+// amiID matches the ID of an Amazon Machine Image.
+var amiID = regexp.MustCompile(`^ami-[0-9a-f]{8}([0-9a-f]{9})?$`)
+
+// checkDataType checks that the value of a parameter with a data type other
+// than text can be read into a value of type ty. AMI IDs of aws:ec2:image
+// parameters, and the JSON documents of aws:ssm:integration parameters, can
+// only be read into strings, types with a converter or types implementing
+// encoding.TextUnmarshaler or json.Unmarshaler.
+//
+// Parameters with a data type this package does not know are read as text.
+func (s *ParamStore) checkDataType(p types.Parameter, ty reflect.Type) error {
+	switch aws.ToString(p.DataType) {
+	case "", dataTypeText:
+		return nil
+	case dataTypeImage:
+		if !amiID.MatchString(*p.Value) {
+			return fmt.Errorf("%s parameter is not an AMI ID", dataTypeImage)
+		}
+	case dataTypeIntegration:
+	default:
+		return nil
+	}
+	if s.types[ty] {
+		return nil
+	}
+	for ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if ty.Kind() != reflect.String && !s.types[ty] && !isText(ty) && !isJSON(ty) {
+		return fmt.Errorf("cannot read %s parameter into %s", *p.DataType, ty)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package ssm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestCheckDataType_error(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		dataTypeParam("/ami", "ami-01234567", "aws:ec2:image"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithParseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		AMI int `ssm:"ami"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	if err == nil {
+		t.Fatal("Want error")
+	}
+	want := "/ami: cannot read aws:ec2:image parameter into int"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("Error = %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,141 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// WithDecryption sets whether SecureString parameters are decrypted. Defaults
+// to true. If false, the encrypted values are read, which does not require
+// permission to use the KMS key.
+//
+// A single field can be read without decryption with the nodecrypt tag
+// option, for example to check whether the parameter is encrypted:
+//
+//   Token string `ssm:"token,nodecrypt"`
+func WithDecryption(decrypt bool) Option {
+	return func(s *ParamStore) {
+		s.decrypt = decrypt
+	}
+}
+
+// decrypts reports whether the parameter is read with decryption by the read
+// of ctx.
+func (s *ParamStore) decrypts(ctx context.Context, name string) bool {
+	return s.decrypt && !readOptionsFrom(ctx).noDecrypt[name]
+}
+
+// cacheKey returns the name the parameter is cached under by the read of ctx.
+// Parameters read without decryption are cached separately, so the encrypted
+// values are not served to reads with decryption.
+func (s *ParamStore) cacheKey(ctx context.Context, name string) string {
+	if s.decrypts(ctx, name) {
+		return name
+	}
+	return name + encryptedSuffix
+}
+
+// encryptedSuffix is appended to the cache keys of parameters read without
+// decryption. It is not valid in parameter names.
+const encryptedSuffix = "#encrypted"
+
+// WithDecryptionFallback reads the parameters that do not need decryption
+// when SecureString parameters cannot be decrypted, for example when KMS is
+// slow or unavailable. If a batch of parameters read with decryption fails, or
+// takes longer than timeout, it is read again without decryption and the
+// SecureString parameters in it are retried separately. A timeout of 0 waits
+// for the request to complete.
+//
+// If the retry fails, Read sets the fields of the other parameters and returns
+// a DecryptionError naming the parameters that could not be read:
+//
+//   err := ps.Read(ctx, &cfg)
+//   var derr ssm.DecryptionError
+//   if errors.As(err, &derr) {
+//       // cfg is set, except for the fields of derr.Names()
+//   }
+func WithDecryptionFallback(timeout time.Duration) Option {
+	return func(s *ParamStore) {
+		s.decryptFallback = true
+		s.decryptTimeout = timeout
+	}
+}
+
+// A DecryptionError is returned with WithDecryptionFallback when SecureString
+// parameters could not be read. The other parameters were read.
+type DecryptionError struct {
+	names []string
+	err   error
+}
+
+func (e DecryptionError) Error() string {
+	return fmt.Sprintf("decrypt %s: %v", strings.Join(e.names, ", "), e.err)
+}
+
+// Names returns the names of the parameters that could not be read.
+func (e DecryptionError) Names() []string {
+	return e.names
+}
+
+// Unwrap returns the error of the request with decryption.
+func (e DecryptionError) Unwrap() error {
+	return e.err
+}
+
+// fallback reads the batch without decryption after reading it with
+// decryption failed with cause. The SecureString parameters in the batch are
+// retried with decryption. If the retry fails, the other parameters are
+// returned along with the names of the SecureString parameters and the
+// error.
+func (s *ParamStore) fallback(ctx context.Context, b batch, cause error) ([]types.Parameter, []string, error) {
+	s.log(ctx, slog.LevelWarn, "read with decryption failed, reading without decryption",
+		slog.String("error", cause.Error()),
+	)
+	plain, err := s.getParameters(ctx, batch{names: b.names})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Matched by the requested names, so the secure parameters can be
+	// requested again.
+	plain = matchARNs(b.names, matchSelectors(plain))
+
+	var (
+		params []types.Parameter
+		secure []string
+	)
+	for _, p := range plain {
+		if p.Type == types.ParameterTypeSecureString {
+			secure = append(secure, *p.Name)
+		} else {
+			params = append(params, p)
+		}
+	}
+	if len(secure) == 0 {
+		return params, nil, nil
+	}
+	decrypted, err := s.getParameters(ctx, batch{names: secure, decrypt: true})
+	if err != nil {
+		return params, secure, err
+	}
+	return append(params, decrypted...), nil, nil
+}
+
+// without returns the names that are not in remove.
+func without(names, remove []string) []string {
+	skip := make(map[string]bool, len(remove))
+	for _, name := range remove {
+		skip[name] = true
+	}
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if !skip[name] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
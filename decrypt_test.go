@@ -0,0 +1,151 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithDecryptionFallback(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/host", "db.example.com"),
+			secureStringParam("/password", "hunter2"),
+			secureStringParam("/token", "secret"),
+		},
+		decryptFail: 100,
+	}
+	ps, err := NewParamStore(WithClient(mock), WithDecryptionFallback(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host     string `ssm:"host"`
+		Password string `ssm:"password"`
+		Token    string `ssm:"token"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	var derr DecryptionError
+	if !errors.As(err, &derr) {
+		t.Fatalf("Read() err = %v, want DecryptionError", err)
+	}
+	if diff := cmp.Diff(derr.Names(), []string{"/password", "/token"}); diff != "" {
+		t.Errorf("Names() (-got +want)\n%s", diff)
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "KMSInvalidStateException" {
+		t.Errorf("Read() err = %v, want KMSInvalidStateException", err)
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "db.example.com"},
+		{path: "Password", value: ""},
+		{path: "Token", value: ""},
+	})
+}
+
+func TestWithDecryptionFallback_Retry(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/host", "db.example.com"),
+			secureStringParam("/password", "hunter2"),
+		},
+		decryptFail: 1,
+	}
+	ps, err := NewParamStore(WithClient(mock), WithDecryptionFallback(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host     string `ssm:"host"`
+		Password string `ssm:"password"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "db.example.com"},
+		{path: "Password", value: "hunter2"},
+	})
+}
+
+func TestWithoutDecryptionFallback(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/host", "db.example.com"),
+			secureStringParam("/password", "hunter2"),
+		},
+		decryptFail: 1,
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host     string `ssm:"host"`
+		Password string `ssm:"password"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	if _, ok := err.(DecryptionError); err == nil || ok {
+		t.Errorf("Read() err = %v, want request error", err)
+	}
+}
+
+func TestNoDecrypt_PerRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{name: "NoCache"},
+		{name: "Cache", options: []Option{WithCache(time.Minute)}},
+		{name: "Stale", options: []Option{WithStaleWhileRevalidate(nil), WithCache(time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSSM{
+				params: []types.Parameter{
+					secureStringParam("/token", "secret"),
+				},
+			}
+			ps, err := NewParamStore(append([]Option{WithClient(mock)}, tt.options...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var encrypted struct {
+				Token string `ssm:"token,nodecrypt"`
+			}
+			if err := ps.Read(context.Background(), &encrypted); err != nil {
+				t.Fatal(err)
+			}
+			check(t, encrypted, []value{
+				{path: "Token", value: "<ENCRYPTED>"},
+			})
+
+			var decrypted struct {
+				Token string `ssm:"token"`
+			}
+			if err := ps.Read(context.Background(), &decrypted); err != nil {
+				t.Fatal(err)
+			}
+			check(t, decrypted, []value{
+				{path: "Token", value: "secret"},
+			})
+
+			encrypted.Token = ""
+			if err := ps.Read(context.Background(), &encrypted); err != nil {
+				t.Fatal(err)
+			}
+			check(t, encrypted, []value{
+				{path: "Token", value: "<ENCRYPTED>"},
+			})
+		})
+	}
+}
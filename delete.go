@@ -0,0 +1,107 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// A DeleteError is returned by DeleteAll when one or more parameters could
+// not be deleted.
+type DeleteError struct {
+	// Failed holds the reason each parameter was not deleted, by name.
+	Failed map[string]error
+}
+
+func (e DeleteError) Error() string {
+	names := make([]string, 0, len(e.Failed))
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %v", name, e.Failed[name])
+	}
+	return fmt.Sprintf("delete failed: %s", strings.Join(parts, "; "))
+}
+
+// DeleteAll deletes all parameters under prefix. This is intended for
+// tearing down ephemeral environments:
+//
+//   err := ps.DeleteAll(ctx, "preview/pr-123", func(names []string) bool {
+//       log.Printf("Deleting %d parameters", len(names))
+//       return len(names) < 100
+//   })
+//
+// confirm is called with the names of the parameters before anything is
+// deleted. If it returns false, no parameters are deleted. confirm is not
+// called if there are no parameters under prefix.
+//
+// Parameters are deleted in batches of 10. If some parameters could not be
+// deleted, the remaining batches are still deleted and a DeleteError is
+// returned.
+func (s *ParamStore) DeleteAll(ctx context.Context, prefix string, confirm func(names []string) bool) error {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		return fmt.Errorf("prefix is required")
+	}
+	if confirm == nil {
+		return fmt.Errorf("confirm is required")
+	}
+
+	params, err := s.getPath(ctx, prefix, false)
+	if err != nil {
+		return fmt.Errorf("list %s: %v", prefix, err)
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = *p.Name
+	}
+	sort.Strings(names)
+	if !confirm(names) {
+		return nil
+	}
+
+	failed := make(map[string]error)
+	for i := 0; i < len(names); i += maxNames {
+		end := i + maxNames
+		if end > len(names) {
+			end = len(names)
+		}
+		batch := names[i:end]
+		input := &ssm.DeleteParametersInput{
+			Names: batch,
+		}
+		var resp *ssm.DeleteParametersOutput
+		err := s.send(ctx, func() (err error) {
+			resp, err = s.cli.DeleteParameters(ctx, input, s.clientOptions()...)
+			return err
+		})
+		if err != nil {
+			for _, name := range batch {
+				failed[name] = err
+			}
+			continue
+		}
+		for _, name := range resp.InvalidParameters {
+			failed[name] = fmt.Errorf("not found")
+		}
+		s.forget(ctx, resp.DeletedParameters)
+	}
+	s.log(ctx, slog.LevelInfo, "deleted parameters",
+		slog.String("prefix", prefix),
+		slog.Int("count", len(names)-len(failed)),
+	)
+	if len(failed) > 0 {
+		return DeleteError{Failed: failed}
+	}
+	return nil
+}
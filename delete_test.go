@@ -0,0 +1,105 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_DeleteAll(t *testing.T) {
+	mock := &mockSSM{params: append(appParams(12), stringParam("/other", "keep"))}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var confirmed []string
+	err = ps.DeleteAll(context.Background(), "/", func(names []string) bool { return true })
+	if err == nil {
+		t.Fatal("Want error for empty prefix")
+	}
+	err = ps.DeleteAll(context.Background(), "app", func(names []string) bool {
+		confirmed = names
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(confirmed) != 12 {
+		t.Errorf("confirm called with %d names, want 12", len(confirmed))
+	}
+	if mock.deleteCalls != 2 {
+		t.Errorf("DeleteParameters called %d times, want 2", mock.deleteCalls)
+	}
+	if got := mock.getParams(); len(got) != 1 || *got[0].Name != "/other" {
+		t.Errorf("Remaining parameters = %d, want only /other", len(got))
+	}
+}
+
+func TestParamStore_DeleteAll_notConfirmed(t *testing.T) {
+	mock := &mockSSM{params: appParams(3)}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ps.DeleteAll(context.Background(), "app", func(names []string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mock.deleteCalls != 0 {
+		t.Errorf("DeleteParameters called %d times, want 0", mock.deleteCalls)
+	}
+	if got := len(mock.getParams()); got != 3 {
+		t.Errorf("Remaining parameters = %d, want 3", got)
+	}
+}
+
+func TestParamStore_DeleteAll_failed(t *testing.T) {
+	mock := &mockSSM{
+		params:     appParams(12),
+		failDelete: "/app/p11",
+		deleteErr:  errors.New("AccessDeniedException"),
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ps.DeleteAll(context.Background(), "app", func(names []string) bool { return true })
+	derr, ok := err.(DeleteError)
+	if !ok {
+		t.Fatalf("DeleteAll() err = %v, want DeleteError", err)
+	}
+	t.Logf("Got expected error: %v", err)
+
+	// The second batch failed, the first was deleted.
+	var failed []string
+	for name := range derr.Failed {
+		failed = append(failed, name)
+	}
+	if len(failed) != 2 {
+		t.Errorf("Failed = %v, want 2 names", failed)
+	}
+	var remaining []string
+	for _, p := range mock.getParams() {
+		remaining = append(remaining, *p.Name)
+	}
+	want := []string{"/app/p10", "/app/p11"}
+	if !reflect.DeepEqual(remaining, want) {
+		t.Errorf("Remaining = %v, want %v", remaining, want)
+	}
+}
+
+// appParams returns n parameters under /app.
+func appParams(n int) []types.Parameter {
+	params := make([]types.Parameter, n)
+	for i := range params {
+		params[i] = stringParam(fmt.Sprintf("/app/p%02d", i), "value")
+	}
+	return params
+}
@@ -0,0 +1,44 @@
+package ssm
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// warnDeprecated logs a warning if a field the parameter was read into has
+// the deprecated tag option. The option may have a message, such as the
+// parameter replacing it:
+//
+//   OldHost string `ssm:"old_host,optional,deprecated='use host'"`
+//
+// The warning is logged once per parameter, so refreshing does not repeat it.
+func (s *ParamStore) warnDeprecated(ctx context.Context, ty reflect.Type, name string, indices [][]int) {
+	for _, index := range indices {
+		f := structField(ty, index)
+		tag, _ := s.lookupTag(f)
+		_, opts := parseTag(tag)
+		msg, ok := opts.Get("deprecated")
+		if !ok && !opts.Contains("deprecated") {
+			continue
+		}
+
+		s.mu.Lock()
+		warned := s.deprecated[name]
+		s.deprecated[name] = true
+		s.mu.Unlock()
+		if warned {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.String("name", name),
+			slog.String("field", strings.Join(fieldPath(ty, index), ".")),
+		}
+		if msg != "" {
+			attrs = append(attrs, slog.String("message", msg))
+		}
+		s.log(ctx, slog.LevelWarn, "deprecated parameter is set", attrs...)
+		return
+	}
+}
@@ -0,0 +1,59 @@
+package ssm
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeprecated(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelWarn,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "new.example.com"),
+		stringParam("/old_host", "old.example.com"),
+		stringParam("/legacy", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host    string `ssm:"host"`
+		OldHost string `ssm:"old_host,optional,deprecated='use host'"`
+		Legacy  string `ssm:"legacy,deprecated"`
+		Unset   string `ssm:"unset,optional,deprecated"`
+	}
+	for i := 0; i < 2; i++ {
+		if err := ps.Read(context.Background(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "new.example.com"},
+		{path: "OldHost", value: "old.example.com"},
+		{path: "Legacy", value: "1"},
+		{path: "Unset", value: ""},
+	})
+
+	want := `level=WARN msg="deprecated parameter is set" name=/legacy field=Legacy
+level=WARN msg="deprecated parameter is set" name=/old_host field=OldHost message="use host"
+`
+	if diff := cmp.Diff(buf.String(), want); diff != "" {
+		t.Errorf("Log (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,195 @@
+// Package diskcache provides an ssm.Cache that stores parameters in an
+// encrypted file, such as in /tmp on AWS Lambda. An execution environment that
+// is reused loads the parameters from the file instead of requesting them
+// from SSM, until they expire.
+//
+//   key, _ := hex.DecodeString(os.Getenv("CACHE_KEY"))
+//   c, err := diskcache.New("/tmp/ssm.cache", key)
+//   if err != nil {
+//       // Handle error
+//   }
+//   ps, err := ssm.NewParamStore(ssm.WithSharedCache(c, 5*time.Minute))
+//
+// The file is encrypted with AES-GCM. A file that cannot be decrypted, for
+// example because the key changed, is ignored and replaced on the next write.
+package diskcache
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/akupila/ssm"
+)
+
+// Cache is an ssm.Cache that stores parameters in an encrypted file.
+type Cache struct {
+	path string
+	aead cipher.AEAD
+
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string]entry
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+var _ ssm.Cache = (*Cache)(nil)
+
+type entry struct {
+	Param   ssm.CachedParameter `json:"param"`
+	Expires time.Time           `json:"expires"`
+}
+
+// New creates a new cache stored at path. key is the AES key used to encrypt
+// the file, and must be 16, 24 or 32 bytes long.
+//
+// The file is created on the first Set. Values of SecureString parameters are
+// only protected by the encryption of the file, so the key must be kept secret
+// and not stored alongside the file.
+func New(path string, key []byte) (*Cache, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create gcm: %v", err)
+	}
+	return &Cache{
+		path:    path,
+		aead:    aead,
+		entries: make(map[string]entry),
+		now:     time.Now,
+	}, nil
+}
+
+// Get returns the cached parameter with the given name.
+func (c *Cache) Get(ctx context.Context, name string) (ssm.CachedParameter, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return ssm.CachedParameter{}, false, err
+	}
+	e, ok := c.entries[name]
+	if !ok || !c.now().Before(e.Expires) {
+		return ssm.CachedParameter{}, false, nil
+	}
+	return e.Param, true, nil
+}
+
+// Set stores the parameter for the duration of ttl and writes the file.
+func (c *Cache) Set(ctx context.Context, param ssm.CachedParameter, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+	c.entries[param.Name] = entry{
+		Param:   param,
+		Expires: c.now().Add(ttl),
+	}
+	return c.save()
+}
+
+// Delete removes the parameter with the given name and writes the file.
+func (c *Cache) Delete(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+	if _, ok := c.entries[name]; !ok {
+		return nil
+	}
+	delete(c.entries, name)
+	return c.save()
+}
+
+// load reads the entries from the file, once.
+func (c *Cache) load() error {
+	if c.loaded {
+		return nil
+	}
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		c.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read cache: %v", err)
+	}
+	c.loaded = true
+
+	n := c.aead.NonceSize()
+	if len(data) < n {
+		return nil
+	}
+	plain, err := c.aead.Open(nil, data[:n], data[n:], nil)
+	if err != nil {
+		// Written with a different key
+		return nil
+	}
+	var entries map[string]entry
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil
+	}
+	now := c.now()
+	for name, e := range entries {
+		if now.Before(e.Expires) {
+			c.entries[name] = e
+		}
+	}
+	return nil
+}
+
+// save encrypts the unexpired entries and replaces the file.
+func (c *Cache) save() error {
+	now := c.now()
+	for name, e := range c.entries {
+		if !now.Before(e.Expires) {
+			delete(c.entries, name)
+		}
+	}
+	plain, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("encode cache: %v", err)
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("generate nonce: %v", err)
+	}
+	data := c.aead.Seal(nonce, nonce, plain, nil)
+
+	// Write to a temporary file first, so a concurrent reader never sees a
+	// partially written file.
+	f, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("write cache: %v", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("write cache: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("write cache: %v", err)
+	}
+	if err := os.Rename(f.Name(), c.path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("write cache: %v", err)
+	}
+	return nil
+}
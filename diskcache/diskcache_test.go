@@ -0,0 +1,88 @@
+package diskcache
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akupila/ssm"
+	"github.com/google/go-cmp/cmp"
+)
+
+var testKey = bytes.Repeat([]byte{1}, 32)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ssm.cache")
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	newCache := func(key []byte) *Cache {
+		t.Helper()
+		c, err := New(path, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.now = func() time.Time { return now }
+		return c
+	}
+	ctx := context.Background()
+
+	c := newCache(testKey)
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() = %t, %v, want not found", ok, err)
+	}
+
+	want := ssm.CachedParameter{
+		Name:    "/foo",
+		Type:    "SecureString",
+		Value:   "secret",
+		Version: 2,
+	}
+	if err := c.Set(ctx, want, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Error("File contains plain text value")
+	}
+
+	// Loaded from the file
+	c = newCache(testKey)
+	got, ok, err := c.Get(ctx, "/foo")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %t, %v, want found", ok, err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Get() (-got +want)\n%s", diff)
+	}
+
+	// Different key
+	other := newCache(bytes.Repeat([]byte{2}, 32))
+	if _, ok, err := other.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() with other key = %t, %v, want not found", ok, err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() after expiry = %t, %v, want not found", ok, err)
+	}
+
+	now = now.Add(-time.Minute)
+	if err := c.Delete(ctx, "/foo"); err != nil {
+		t.Fatal(err)
+	}
+	c = newCache(testKey)
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() after Delete() = %t, %v, want not found", ok, err)
+	}
+}
+
+func TestNew_invalidKey(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "ssm.cache"), []byte("short")); err == nil {
+		t.Error("Want error")
+	}
+}
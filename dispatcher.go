@@ -0,0 +1,170 @@
+package ssm
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// A Dispatcher sends the updates of a config to several components in a
+// process, each receiving only the updates of the fields it subscribed to.
+// Components do not need to diff the whole config to find out whether an
+// update concerns them:
+//
+//   updates, err := ps.Updates(ctx, &Config{})
+//   if err != nil {
+//       return err
+//   }
+//   d := ssm.NewDispatcher(updates)
+//   db, err := d.Subscribe("DB")
+//   ...
+//   for u := range db {
+//       cfg := u.Config.(*Config)
+//       ...
+//   }
+//
+// A Dispatcher is safe for concurrent use.
+type Dispatcher struct {
+	mu     sync.Mutex
+	subs   map[<-chan Update]*subscriber
+	closed bool
+}
+
+type subscriber struct {
+	patterns []string
+	ch       chan Update
+}
+
+// NewDispatcher creates a Dispatcher that sends the updates received from
+// updates, usually the channel returned by Updates. The channels of the
+// subscribers are closed when updates is closed.
+func NewDispatcher(updates <-chan Update) *Dispatcher {
+	d := &Dispatcher{subs: make(map[<-chan Update]*subscriber)}
+	go func() {
+		for u := range updates {
+			d.dispatch(u)
+		}
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		for _, sub := range d.subs {
+			close(sub.ch)
+		}
+		d.subs = nil
+		d.closed = true
+	}()
+	return d
+}
+
+// Subscribe returns a channel that receives the updates changing a field
+// matching one of the patterns. Patterns are matched against the paths of the
+// fields, such as DB.Password, with the syntax of path.Match, where * does
+// not match a dot. A pattern matching a struct field also matches the fields
+// nested in it, so DB matches DB.Password:
+//
+//   d.Subscribe("DB", "*.Timeout")
+//
+// The Changed field of an Update only holds the paths matching the patterns.
+// Updates are not queued: if the previous update has not been received when
+// the next one is sent, they are merged, so a slow subscriber receives the
+// latest config with every field changed since it last received one. A slow
+// subscriber does not delay the others.
+//
+// An error is returned if a pattern is malformed. If the updates channel was
+// closed, the returned channel is closed.
+func (d *Dispatcher) Subscribe(patterns ...string) (<-chan Update, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(globPath(pattern), ""); err != nil {
+			return nil, fmt.Errorf("pattern %q: %v", pattern, err)
+		}
+	}
+	sub := &subscriber{
+		patterns: patterns,
+		ch:       make(chan Update, 1),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		close(sub.ch)
+		return sub.ch, nil
+	}
+	d.subs[sub.ch] = sub
+	return sub.ch, nil
+}
+
+// Unsubscribe stops sending updates to a channel returned by Subscribe, and
+// closes it.
+func (d *Dispatcher) Unsubscribe(ch <-chan Update) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sub, ok := d.subs[ch]
+	if !ok {
+		return
+	}
+	delete(d.subs, ch)
+	close(sub.ch)
+}
+
+// dispatch sends u to the subscribers with a pattern matching a changed
+// field.
+func (d *Dispatcher) dispatch(u Update) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, sub := range d.subs {
+		var changed []string
+		for _, field := range u.Changed {
+			if sub.matches(field) {
+				changed = append(changed, field)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		next := Update{Config: u.Config, Changed: changed}
+		select {
+		case prev := <-sub.ch:
+			// Not received yet
+			next.Changed = mergeChanged(prev.Changed, next.Changed)
+		default:
+		}
+		// Only dispatch sends on the channel, so the buffer is empty
+		sub.ch <- next
+	}
+}
+
+// matches reports whether the field, or a struct it is nested in, matches one
+// of the patterns of the subscriber.
+func (sub *subscriber) matches(field string) bool {
+	p := globPath(field)
+	for _, pattern := range sub.patterns {
+		pattern = globPath(pattern)
+		for name := p; name != "."; name = path.Dir(name) {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// globPath replaces the dots separating the names of fields with slashes, so
+// the path can be matched with path.Match.
+func globPath(field string) string {
+	return strings.ReplaceAll(field, ".", "/")
+}
+
+// mergeChanged returns the paths in a followed by the paths in b that are not
+// in a.
+func mergeChanged(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	merged := append([]string(nil), a...)
+	for _, p := range a {
+		seen[p] = true
+	}
+	for _, p := range b {
+		if !seen[p] {
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,149 @@
+package ssm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func receiveUpdate(t *testing.T, ch <-chan Update) Update {
+	t.Helper()
+	select {
+	case u, ok := <-ch:
+		if !ok {
+			t.Fatal("Channel closed")
+		}
+		return u
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for update")
+		return Update{}
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	updates := make(chan Update)
+	d := NewDispatcher(updates)
+
+	db, err := d.Subscribe("DB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeouts, err := d.Subscribe("*.Timeout", "Timeout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updates <- Update{Config: 1, Changed: []string{"DB.Host", "HTTP.Timeout", "Name"}}
+	if u := receiveUpdate(t, db); !reflect.DeepEqual(u.Changed, []string{"DB.Host"}) || u.Config != 1 {
+		t.Errorf("DB update = %+v", u)
+	}
+	if u := receiveUpdate(t, timeouts); !reflect.DeepEqual(u.Changed, []string{"HTTP.Timeout"}) {
+		t.Errorf("Timeout update = %+v", u)
+	}
+
+	updates <- Update{Config: 2, Changed: []string{"Name"}}
+	updates <- Update{Config: 3, Changed: []string{"DB.Pool.Size"}}
+	if u := receiveUpdate(t, db); !reflect.DeepEqual(u.Changed, []string{"DB.Pool.Size"}) || u.Config != 3 {
+		t.Errorf("DB update = %+v", u)
+	}
+	select {
+	case u := <-timeouts:
+		t.Errorf("Unexpected update %+v", u)
+	default:
+	}
+
+	d.Unsubscribe(timeouts)
+	if _, ok := <-timeouts; ok {
+		t.Error("Want channel closed after Unsubscribe")
+	}
+
+	close(updates)
+	if _, ok := <-db; ok {
+		t.Error("Want channel closed after updates closed")
+	}
+	late, err := d.Subscribe("DB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := <-late; ok {
+		t.Error("Want channel closed when subscribing after updates closed")
+	}
+}
+
+func TestDispatcher_merge(t *testing.T) {
+	updates := make(chan Update)
+	d := NewDispatcher(updates)
+	slow, err := d.Subscribe("*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, err := d.Subscribe("B")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updates <- Update{Config: 1, Changed: []string{"A"}}
+	updates <- Update{Config: 2, Changed: []string{"B", "A"}}
+	updates <- Update{Config: 3, Changed: []string{"C"}}
+	if u := receiveUpdate(t, fast); u.Config != 2 {
+		t.Errorf("Fast update = %+v, want config 2", u)
+	}
+	// Sending on an unbuffered channel returns before the update is
+	// dispatched, so wait for the last one to be merged.
+	close(updates)
+	for range fast {
+	}
+	u := receiveUpdate(t, slow)
+	if want := []string{"A", "B", "C"}; !reflect.DeepEqual(u.Changed, want) || u.Config != 3 {
+		t.Errorf("Slow update = %+v, want config 3 with %v", u, want)
+	}
+}
+
+func TestDispatcher_invalidPattern(t *testing.T) {
+	d := NewDispatcher(make(chan Update))
+	if _, err := d.Subscribe("DB.[a"); err == nil {
+		t.Error("Want error")
+	}
+}
+
+func TestDispatcher_updates(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/db/host", "a"),
+		stringParam("/name", "app"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		DB struct {
+			Host string `ssm:"host"`
+		} `ssm:"db"`
+		Name string `ssm:"name"`
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := ps.Updates(ctx, &config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDispatcher(updates)
+	db, err := d.Subscribe("DB")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.setParam(stringParam("/name", "other"))
+	mock.setParam(stringParam("/db/host", "b"))
+	u := receiveUpdate(t, db)
+	if !reflect.DeepEqual(u.Changed, []string{"DB.Host"}) {
+		t.Errorf("Changed = %v, want [DB.Host]", u.Changed)
+	}
+	if got := u.Config.(*config).DB.Host; got != "b" {
+		t.Errorf("DB.Host = %q, want %q", got, "b")
+	}
+}
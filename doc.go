@@ -1,7 +1,7 @@
 // Package ssm provides a way to read config values from AWS Systems Manager
 // Parameter Store.
 //
-// Struct tags
+// # Struct tags
 //
 // Struct tags determine what parameters to get from SSM:
 //
@@ -17,7 +17,89 @@
 // The name of the struct tag to use can be set by passing WithTag to
 // NewParamStore. Defaults to `ssm`.
 //
-// Nested values
+// Fields with the tag `ssm:"-"` are skipped, like with encoding/json. This
+// allows sharing a tag name with other loaders.
+//
+// Options may follow the name, separated by commas. If WithSplitWords is
+// passed, the name may be left out, in which case it is derived from the field
+// name: ClientID is read from client_id. WithAutoKeys also reads exported
+// fields without a struct tag in the same way. WithKeyFunc replaces the
+// conversion to snake_case with a custom naming strategy, such as camelCase or
+// kebab-case.
+//
+// Read returns a NotFoundError if a parameter does not exist, unless the field
+// has the optional option. Optional fields are left unchanged if the parameter
+// does not exist:
+//
+//   Timeout string `ssm:"timeout,optional"`
+//
+// WithIgnoreMissing makes every field optional, reporting the names of the
+// missing parameters to a callback instead.
+//
+// The default option sets the value to use if the parameter does not exist.
+// It is converted in the same way as a parameter value:
+//
+//   Port  int      `ssm:"port,default=5432"`
+//   Hosts []string `ssm:"hosts,default='a,b'"`
+//
+// Options with values are written as key=value. Values containing commas must
+// be quoted with single quotes:
+//
+//   Host string `ssm:"host,desc='Database host, without port'"`
+//
+// The desc option describes the parameter. WriteExample uses it to generate an
+// example file, such as a .env.example, listing every parameter the config
+// needs.
+//
+// The secure option requires the parameter to be a SecureString. Read returns
+// a ValidationError if a secret was stored as plain text:
+//
+//   Password string `ssm:"password,secure"`
+//
+// The version option pins a field to a specific version of the parameter,
+// for reproducible deployments. Other fields read the latest version:
+//
+//   Host string `ssm:"host,version=3"`
+//
+// The label option reads the version with the label instead, such as a
+// version approved for production. WithLabel selects a label for every
+// parameter.
+//
+// The minversion option requires the parameter to be at least the given
+// version, so new code fails to start until a rotation or migration has
+// happened. Read returns a ValidationError otherwise:
+//
+//   Key string `ssm:"key,minversion=4"`
+//
+// The format option checks that the value is a hostname, email, arn, s3uri
+// or semver. Read returns a ValidationError listing every parameter with an
+// invalid value:
+//
+//   Host string `ssm:"host,format=hostname"`
+//
+// WithNamingPolicy and WithNamingPattern check every name against the naming
+// conventions of an organization, such as lowercase names of limited depth.
+// Names are checked before any parameter is read or written, and Read returns
+// a ValidationError listing every violation.
+//
+// The nodecrypt option reads a SecureString parameter without decrypting it.
+// WithDecryption(false) reads every parameter without decryption.
+// WithDecryptionFallback still reads the other parameters if SecureString
+// parameters cannot be decrypted, returning a DecryptionError along with the
+// partial config.
+//
+// The envs option lists the environments a field is read in. The environment
+// is set with WithEnvironment:
+//
+//   Replicas int `ssm:"replicas,envs=prod|staging"`
+//
+// The deprecated option marks a parameter that is being retired. A warning is
+// logged with WithLogger the first time the parameter is read, with the
+// message of the option if set:
+//
+//   OldHost string `ssm:"old_host,optional,deprecated='use host'"`
+//
+// # Nested values
 //
 // Nested struct value are allowed. When present, the name to read from SSM is
 // constructed by the path to the value:
@@ -34,11 +116,61 @@
 //       } `ssm:"auth0"`
 //   }
 //
-// Options
+// The inline (or squash) option reads the fields of a nested struct at the
+// level of the parent, without adding the name of the field to the path. This
+// allows reusing a struct in configs with different layouts:
+//
+//   type AWS struct {
+//       Region string `ssm:"region"`
+//   }
+//
+//   type Config struct {
+//       AWS  `ssm:",inline"` // /region
+//       Host string          `ssm:"host"`
+//   }
+//
+// Embedded structs without a name in the struct tag are read inline, like
+// with encoding/json, so the tag above may be left out. Giving the embedded
+// struct a name reads its fields under the name instead.
+//
+// Several fields may read the same parameter, for example a region used by
+// more than one nested struct.
+//
+// Names starting with / are absolute. They are not prefixed with the name of
+// the parent struct or the prefix set with WithPrefix, allowing a field to read
+// a parameter shared by several applications:
+//
+//   KMSKeyID string `ssm:"/shared/kms_key_id"`
+//
+// Names starting with ~/ are relative to the prefix set with WithPrefix,
+// instead of the parent struct, so a block of config shared by several
+// nested structs does not need to be repeated under each of them. The root
+// option does the same. With WithPrefix("dev"):
+//
+//   type Config struct {
+//       Service struct {
+//           Region string `ssm:"~/region"`    // /dev/region
+//           Bucket string `ssm:"bucket,root"` // /dev/bucket
+//       } `ssm:"service"`
+//   }
+//
+// Parameters shared from another account with AWS Resource Access Manager are
+// read by their full ARN, which is also not prefixed:
+//
+//   DB string `ssm:"arn:aws:ssm:eu-west-1:123456789012:parameter/shared/db"`
+//
+// # Options
 //
 // The behavior can be modified by passing options to NewParamStore. If no
-// options are passed, the external aws config is read for the SSM client, and
-// ssm is used as the struct tag.
+// options are passed, the default aws config is loaded for the SSM client, and
+// ssm is used as the struct tag. A client from aws-sdk-go (v1) can be passed to
+// WithClient by wrapping it with ssmv1.Wrap, from the separate
+// github.com/akupila/ssm/ssmv1 module. WithAPIOptions adds SDK middleware to
+// the requests made to SSM.
+//
+// Requests to SSM identify this package and its version in the User-Agent,
+// so they can be attributed in CloudTrail. WithAppName adds the name of the
+// application.
 //
 // WithPrefix allows all keys to be prefixed with a value. Given the following
 // structure in SSM:
@@ -59,13 +191,248 @@
 //       } `ssm:"db"`
 //   }
 //
+// A TenantStore reads the configs of many tenants, each under a prefix built
+// from a template such as /tenants/{tenant}/app, sharing one ParamStore and
+// its cache between them.
+//
 // Times and durations can be parsed using WithParseTime and WithParseDuration.
+// Numbers and bools, including in StringList slices such as []int and []bool,
+// are parsed with WithParseNumber and WithParseBool.
+// WithParseLocation loads time zone names, such as Europe/Helsinki, into
+// *time.Location fields.
+//
+// Other types can be supported by passing WithConverter. The ssmdecimal and
+// ssmuuid modules use this to add support for decimal.Decimal and uuid.UUID,
+// without adding their dependencies to this module. Converters passed to
+// WithConverterFunc receive the context of the read, so converters that do I/O
+// respect its cancellation.
+//
+// The data type of a parameter is checked before its value is converted.
+// Parameters with the aws:ec2:image data type must hold an AMI ID, and
+// parameters with the aws:ec2:image or aws:ssm:integration data types can only
+// be read into strings, types with a converter or types implementing
+// encoding.TextUnmarshaler, or with the json option.
+//
+// Fields of type url.URL are parsed from absolute URLs, so malformed
+// endpoints are reported when the config is read.
+//
+// Numbers that exceed the precision of int64 and float64, such as token
+// amounts with 18 decimals, can be read into big.Int, big.Float and big.Rat,
+// or slices of them from StringList parameters. big.Float values are parsed
+// with enough precision to hold every digit.
+//
+// IP addresses and CIDR blocks can be read into net.IP, net.IPNet, netip.Addr
+// and netip.Prefix, or slices of them from StringList parameters, so network
+// allow-lists can be bound directly to typed fields:
+//
+//   Allow []netip.Prefix `ssm:"allow"` // 10.0.0.0/8,172.16.0.0/12
+//
+// Types implementing encoding.TextUnmarshaler, such as netip.Addr, time.Time
+// and regexp.Regexp, are set by calling UnmarshalText with the value of the
+// parameter, so patterns are compiled into *regexp.Regexp fields and an invalid
+// pattern fails the read.
+// Types implementing json.Unmarshaler are set by calling UnmarshalJSON with
+// the value, or with the value as a JSON string if it is not valid JSON, so
+// enum types that decode themselves from a quoted name can be read from a
+// plain value.
+//
+// Other types implementing encoding.BinaryUnmarshaler are read from base64
+// encoded parameters. This allows storing compact binary values, such as
+// keys or serialized protobuf messages, in a single parameter. Standard and
+// URL-safe base64 are accepted, with or without padding, and line breaks are
+// ignored.
+//
+// # Pointers and null values
+//
+// Pointer fields are allocated only when the parameter is read. Existing
+// pointers are reused, so a field that is not set remains nil:
 //
-// Slices
+//   type Config struct {
+//       Host *string `ssm:"host"`
+//   }
+//
+// The database/sql types sql.NullString, sql.NullInt64, sql.NullInt32,
+// sql.NullInt16, sql.NullByte, sql.NullFloat64, sql.NullBool and sql.NullTime
+// may be used to distinguish a parameter that was read from the zero value, and
+// passed as is to database code. Valid is set whenever the parameter exists,
+// including when its value is empty. sql.NullBool accepts the same values as
+// WithParseBool, and sql.NullTime is parsed as RFC 3339.
+//
+// WithEmptyAsMissing instead treats parameters with an empty value as if they
+// did not exist.
+//
+// # Slices
 //
 // If the parameter type is StringList, the value can be assigned to a slice.
 // Conversion rules apply to items within the slice, allowing for example []int
-// to be used.
+// to be used. Items may be pointers, such as []*string, and the slice itself
+// may be a pointer, such as *[]int.
+//
+// StringList values are separated by commas. Items containing commas can be
+// stored in a String parameter with another separator, set with the sep
+// option:
+//
+//   Hosts []string `ssm:"hosts,sep=;"`
+//
+// # Maps
+//
+// Map fields with string keys are read from the parameters directly under a
+// path, one entry per parameter. Conversion rules apply to the values, so
+// StringList parameters can be read into a map[string][]string:
+//
+//   /endpoints/eu-west-1  a.example.com,b.example.com
+//   /endpoints/us-east-1  c.example.com
+//
+//   type Config struct {
+//       Endpoints map[string][]string `ssm:"endpoints"`
+//   }
+//
+// Read returns a NotFoundError if there are no parameters under the path,
+// unless the field is optional. The parameters of map fields are not cached.
+//
+// The json option instead decodes a JSON document stored in a single
+// parameter into the field, which may be a struct, map or slice:
+//
+//   Features map[string]bool `ssm:"features,json"`
+//
+// The kv option reads a map from key=value pairs or a JSON object in a single
+// parameter. Conversion rules apply to the values, and the pairs of a String
+// parameter may be separated by another separator set with the sep option:
+//
+//   Limits map[string]int `ssm:"limits,kv"` // api=100,batch=10
+//
+// # Combining sources
+//
+// A Resolver reads parameters from several providers, each with a priority.
+// Environment variables, local files, fallback prefixes and other ParamStores
+// can be combined, with the conflicts resolved by a MergeRule:
+//
+//   primary, err := ssm.NewParamStore()
+//   ...
+//   r := ssm.NewResolver(ssm.FirstWins).
+//       Add(20, ssm.EnvProvider("/app")).
+//       Add(10, primary).
+//       Add(5, ssm.PrefixFallback(primary, "/app", "/shared")).
+//       Add(0, ssm.FileProvider("defaults.env", "/app"))
+//   ps, err := ssm.NewParamStore(ssm.WithPrefix("app"), ssm.WithResolver(r))
+//
+// # Caching
+//
+// A ParamStore is safe for concurrent use. Converters and callbacks passed to
+// options may be called from several goroutines at once.
+//
+// Concurrent reads of the same parameters share a single request to SSM, so
+// reading the config from many goroutines at once does not multiply the
+// number of requests.
+//
+// The throughput of SSM is limited per account and region. A Scheduler passed
+// to WithScheduler limits the requests of several ParamStores in a process
+// together.
+//
+// WithAdaptiveFetch measures the latency and throttling of reads to choose
+// between requesting parameters by name and reading the whole prefix, and how
+// many names to request at a time, instead of tuning WithPathFetch and
+// WithMaxConcurrency by hand.
+//
+// WithReadBudget limits the time Read spends fetching parameters. If the
+// budget runs out, the fields that were fetched are set and a PartialError
+// lists the others, so latency-critical services can proceed with a partial
+// config.
+//
+// Parameters can be cached in memory between reads by passing WithCache. A
+// context created with BypassCache reads the parameters from SSM regardless.
+//
+// The ttl= tag option overrides the TTL of a single parameter, so values that
+// rotate often can be refreshed sooner than the rest of the config:
+//
+//   type Config struct {
+//       Token string `ssm:"token,ttl=30s"`
+//       Host  string `ssm:"host,ttl=6h"`
+//   }
+//
+// Parameters can also be cached in a Cache passed to WithSharedCache. A cache
+// created with NewMemoryCache can be shared by several ParamStores in the same
+// process. The rediscache and dynamocache modules provide caches that are
+// shared by multiple processes, reducing the number of requests made to SSM.
+// The diskcache subpackage stores parameters in an encrypted file, so a reused
+// AWS Lambda execution environment does not need to request them again.
+//
+// Invalidate removes parameters from the cache before they expire.
+// InvalidateFromEvent does the same for the "Parameter Store Change" events
+// SSM sends to EventBridge, and InvalidateHandler for HTTP requests.
+//
+// WithStaleWhileRevalidate keeps the last values read, so Read continues to
+// succeed while SSM is unavailable. Expired values are returned immediately
+// and refreshed in the background.
+//
+// # Watching for changes
+//
+// SubscribeValue, Updates and Watch poll parameters for changes, at the
+// interval set with WithPollInterval. SubscribeValue sends the values of a
+// single parameter, Updates sends a new copy of the config along with the
+// fields that changed, and Watch sends a Change with the old and new value of
+// each parameter that changed.
+//
+// A Dispatcher shares the updates of a config between the components of a
+// process, each subscribing to the fields it uses by a pattern such as DB or
+// *.Timeout.
+//
+// StartRefresh re-reads the config on a timer in the background. Each refresh
+// reads into a new copy of the config, returned by Current, so the config can
+// be read concurrently without locking. Only fields whose parameters changed
+// are set; if nothing changed, the previous copy is kept.
+//
+// WithOnChange registers a callback for a single parameter, called when a
+// read finds that its value changed.
+//
+// Reconcile keeps a local json or env file in sync with the parameters under
+// a prefix, for processes on the host that cannot read from SSM. The
+// ssm-reconcile command in cmd/ssm-reconcile runs it as a daemon.
+//
+// # Writing
+//
+// Bootstrap reports the parameters a config needs that do not exist yet, with
+// their type and the value suggested by the default= tag option, for
+// onboarding new environments. BootstrapApply creates the missing parameters
+// that have a default, as SecureString if the field has the secure option.
+//
+// WriteMap writes a set of values under a prefix without defining a struct,
+// for tooling and migration scripts. Existing parameters are only replaced if
+// WithOverwrite is passed.
+//
+// DeleteAll deletes all parameters under a prefix, after the names have been
+// passed to a confirmation callback. Rename moves a single parameter to a new
+// name.
+//
+// PromoteLabel moves a label, such as prod, to the versions carrying another
+// label, such as staging, for every parameter under a prefix, so a tested set
+// of parameters can be promoted to the next environment.
+//
+// # Logging
+//
+// Reads can be logged with log/slog by passing WithLogger. Parameter values
+// are never logged.
+//
+// DumpRedacted renders the values read, with values from SecureString
+// parameters redacted, so the config can safely be printed at startup.
+//
+// WithWatermarks records an HMAC of each SecureString value read, keyed with
+// a random key that never leaves the process, so a leaked secret can be traced
+// back to the process and read that produced it without storing the value.
+//
+// ReadWithSummary reads like Read and returns a Summary of the read, with the
+// number of parameters, a hash of their versions and the cache status, to be
+// logged as the first line of a service.
+//
+// WithJournal appends the names and versions of the parameters read to a
+// local Journal each time they change. Journal.ActiveAt returns the configs
+// that were active at a given time, for incident retrospectives.
+//
+// ReadWithReport reads like Read and reports where the value of each field
+// came from: SSM, the cache, a stale value, a default, or a missing optional
+// parameter, along with the region, prefix and version. With
+// WithConversionReport, the report also describes how each value was
+// converted to the type of its field.
 //
 // https://docs.aws.amazon.com/systems-manager/latest/userguide/systems-manager-parameter-store.html
 package ssm
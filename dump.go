@@ -0,0 +1,162 @@
+package ssm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// redacted replaces values that must not be printed.
+const redacted = "*****"
+
+// DumpRedacted renders the values read into cfg, one field per line, for
+// example to be logged at startup:
+//
+//   DB.Host: "db.example.com"
+//   DB.Password: *****
+//
+// Values read from SecureString parameters are redacted. Values that have not
+// been read by the ParamStore are also redacted, as their type is not known.
+// Fields that are not read from SSM are not included.
+func (s *ParamStore) DumpRedacted(cfg interface{}) string {
+	val := reflect.Indirect(reflect.ValueOf(cfg))
+	if val.Kind() != reflect.Struct {
+		return fmt.Sprintf("<not a struct: %T>", cfg)
+	}
+	schema, err := s.schema(val.Type(), s.prefix, nil)
+	if err != nil {
+		return fmt.Sprintf("<invalid: %v>", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var b strings.Builder
+	for _, f := range declFields(schema) {
+		field := fieldByIndex(val, f.index)
+		b.WriteString(strings.Join(fieldPath(val.Type(), f.index), "."))
+		b.WriteString(": ")
+		switch {
+		case s.paramTypes[f.name] == "" || s.paramTypes[f.name] == types.ParameterTypeSecureString:
+			b.WriteString(redacted)
+		case !field.IsValid():
+			b.WriteString("<nil>")
+		case field.Kind() == reflect.String:
+			fmt.Fprintf(&b, "%q", field.String())
+		default:
+			fmt.Fprintf(&b, "%v", field.Interface())
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// schemaField is a field in a schema and the parameter it is read from.
+type schemaField struct {
+	name  string
+	index []int
+}
+
+// declFields returns the fields in the schema in the order they are declared.
+func declFields(schema map[string][][]int) []schemaField {
+	var fields []schemaField
+	for _, name := range sortedNames(schema) {
+		for _, index := range schema[name] {
+			fields = append(fields, schemaField{name: name, index: index})
+		}
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+// declOrder returns the parameter names in the schema in the order the first
+// field reading each parameter is declared.
+func declOrder(schema map[string][][]int) []string {
+	var names []string
+	seen := make(map[string]bool, len(schema))
+	for _, f := range declFields(schema) {
+		if !seen[f.name] {
+			seen[f.name] = true
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// fieldOption returns the value of a key=value tag option of the first field
+// in indices that sets it.
+func (s *ParamStore) fieldOption(ty reflect.Type, indices [][]int, key string) (string, bool) {
+	for _, index := range indices {
+		tag, _ := s.lookupTag(structField(ty, index))
+		_, opts := parseTag(tag)
+		if v, ok := opts.Get(key); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// fieldFlag reports whether any field in indices has the tag option.
+func (s *ParamStore) fieldFlag(ty reflect.Type, indices [][]int, name string) bool {
+	for _, index := range indices {
+		tag, _ := s.lookupTag(structField(ty, index))
+		_, opts := parseTag(tag)
+		if opts.Contains(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// structField returns the nested field at index.
+func structField(ty reflect.Type, index []int) reflect.StructField {
+	var f reflect.StructField
+	for _, i := range index {
+		if ty.Kind() == reflect.Ptr {
+			ty = ty.Elem()
+		}
+		f = ty.Field(i)
+		ty = f.Type
+	}
+	return f
+}
+
+// fieldPath returns the names of the fields in the nested index.
+func fieldPath(ty reflect.Type, index []int) []string {
+	path := make([]string, len(index))
+	for n, i := range index {
+		if ty.Kind() == reflect.Ptr {
+			ty = ty.Elem()
+		}
+		f := ty.Field(i)
+		path[n] = f.Name
+		ty = f.Type
+	}
+	return path
+}
+
+// fieldByIndex returns the value of the nested field. If a nil pointer is
+// encountered the value is invalid.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		v = v.Field(i)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+	}
+	return v
+}
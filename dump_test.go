@@ -0,0 +1,98 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParamStore_DumpRedacted(t *testing.T) {
+	type config struct {
+		Timeout time.Duration `ssm:"timeout"`
+		DB      struct {
+			Host     string `ssm:"host"`
+			Password string `ssm:"password"`
+		} `ssm:"db"`
+		Hosts   []string `ssm:"hosts"`
+		Token   *string  `ssm:"token"`
+		Primary string   `ssm:"db/host"`
+		Ignore  string
+	}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/timeout", "5s"),
+		stringParam("/db/host", "db.example.com"),
+		secureStringParam("/db/password", "secret"),
+		stringListParam("/hosts", "a,b"),
+		secureStringParam("/token", "secret"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithParseDuration())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config
+	cfg.Ignore = "secret"
+
+	// Values not read are redacted
+	want := `Timeout: *****
+DB.Host: *****
+DB.Password: *****
+Hosts: *****
+Token: *****
+Primary: *****
+`
+	if diff := cmp.Diff(ps.DumpRedacted(&cfg), want); diff != "" {
+		t.Errorf("DumpRedacted() before Read (-got +want)\n%s", diff)
+	}
+
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	want = `Timeout: 5s
+DB.Host: "db.example.com"
+DB.Password: *****
+Hosts: [a b]
+Token: *****
+Primary: "db.example.com"
+`
+	if diff := cmp.Diff(ps.DumpRedacted(&cfg), want); diff != "" {
+		t.Errorf("DumpRedacted() (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(ps.DumpRedacted(cfg), want); diff != "" {
+		t.Errorf("DumpRedacted() not pointer (-got +want)\n%s", diff)
+	}
+}
+
+func TestParamStore_DumpRedacted_nilPointer(t *testing.T) {
+	cfg := struct {
+		DB *struct {
+			Host string `ssm:"host"`
+		} `ssm:"db"`
+	}{}
+
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.paramTypes["/db/host"] = types.ParameterTypeString
+
+	want := "DB.Host: <nil>\n"
+	if diff := cmp.Diff(ps.DumpRedacted(&cfg), want); diff != "" {
+		t.Errorf("DumpRedacted() (-got +want)\n%s", diff)
+	}
+}
+
+func TestParamStore_DumpRedacted_notStruct(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ps.DumpRedacted("foo"), "<not a struct: string>"; got != want {
+		t.Errorf("DumpRedacted() = %q, want %q", got, want)
+	}
+}
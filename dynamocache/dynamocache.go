@@ -0,0 +1,134 @@
+// Package dynamocache provides an ssm.Cache backed by DynamoDB, allowing
+// multiple processes to share cached parameters.
+//
+// The table must have a string partition key named "name". To have DynamoDB
+// remove expired items, enable TTL on the "expires" attribute. Expired items
+// are ignored even if they have not yet been removed.
+package dynamocache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/akupila/ssm"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Client is the DynamoDB client. It is implemented by *dynamodb.Client from
+// github.com/aws/aws-sdk-go-v2/service/dynamodb.
+type Client interface {
+	GetItem(ctx context.Context, input *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, input *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// Cache is an ssm.Cache that stores parameters in a DynamoDB table.
+type Cache struct {
+	client Client
+	table  string
+
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+var _ ssm.Cache = (*Cache)(nil)
+
+// New creates a new DynamoDB cache using the given table.
+func New(client Client, table string) *Cache {
+	return &Cache{
+		client: client,
+		table:  table,
+		now:    time.Now,
+	}
+}
+
+// Get returns the cached parameter with the given name.
+func (c *Cache) Get(ctx context.Context, name string) (ssm.CachedParameter, bool, error) {
+	input := &dynamodb.GetItemInput{
+		TableName:      aws.String(c.table),
+		Key:            key(name),
+		ConsistentRead: aws.Bool(true),
+	}
+	resp, err := c.client.GetItem(ctx, input)
+	if err != nil {
+		return ssm.CachedParameter{}, false, fmt.Errorf("dynamodb get item: %v", err)
+	}
+	if len(resp.Item) == 0 {
+		return ssm.CachedParameter{}, false, nil
+	}
+
+	expires, err := number(resp.Item["expires"])
+	if err != nil {
+		return ssm.CachedParameter{}, false, fmt.Errorf("decode %s: expires: %v", name, err)
+	}
+	if c.now().Unix() >= expires {
+		return ssm.CachedParameter{}, false, nil
+	}
+	version, err := number(resp.Item["version"])
+	if err != nil {
+		return ssm.CachedParameter{}, false, fmt.Errorf("decode %s: version: %v", name, err)
+	}
+	p := ssm.CachedParameter{
+		Name:    name,
+		Type:    str(resp.Item["type"]),
+		Value:   str(resp.Item["value"]),
+		Version: version,
+	}
+	return p, true, nil
+}
+
+// Set stores the parameter for the duration of ttl.
+func (c *Cache) Set(ctx context.Context, param ssm.CachedParameter, ttl time.Duration) error {
+	expires := c.now().Add(ttl).Unix()
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(c.table),
+		Item: map[string]types.AttributeValue{
+			"name":    &types.AttributeValueMemberS{Value: param.Name},
+			"type":    &types.AttributeValueMemberS{Value: param.Type},
+			"value":   &types.AttributeValueMemberS{Value: param.Value},
+			"version": &types.AttributeValueMemberN{Value: strconv.FormatInt(param.Version, 10)},
+			"expires": &types.AttributeValueMemberN{Value: strconv.FormatInt(expires, 10)},
+		},
+	}
+	if _, err := c.client.PutItem(ctx, input); err != nil {
+		return fmt.Errorf("dynamodb put item: %v", err)
+	}
+	return nil
+}
+
+// Delete removes the parameter with the given name.
+func (c *Cache) Delete(ctx context.Context, name string) error {
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(c.table),
+		Key:       key(name),
+	}
+	if _, err := c.client.DeleteItem(ctx, input); err != nil {
+		return fmt.Errorf("dynamodb delete item: %v", err)
+	}
+	return nil
+}
+
+func key(name string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"name": &types.AttributeValueMemberS{Value: name},
+	}
+}
+
+func str(v types.AttributeValue) string {
+	if s, ok := v.(*types.AttributeValueMemberS); ok {
+		return s.Value
+	}
+	return ""
+}
+
+func number(v types.AttributeValue) (int64, error) {
+	n, ok := v.(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("not a number")
+	}
+	return strconv.ParseInt(n.Value, 10, 64)
+}
@@ -0,0 +1,94 @@
+package dynamocache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akupila/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	mock := &mockDynamoDB{items: make(map[string]map[string]types.AttributeValue)}
+	c := New(mock, "cache")
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() = %t, %v, want not found", ok, err)
+	}
+
+	want := ssm.CachedParameter{
+		Name:    "/foo",
+		Type:    "SecureString",
+		Value:   "bar",
+		Version: 2,
+	}
+	if err := c.Set(ctx, want, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := number(mock.items["/foo"]["expires"]); got != 1577977505 {
+		t.Errorf("expires = %d, want 1577977505", got)
+	}
+
+	got, ok, err := c.Get(ctx, "/foo")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %t, %v, want found", ok, err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Get() (-got +want)\n%s", diff)
+	}
+
+	// Expired but not yet removed by DynamoDB
+	now = now.Add(time.Minute)
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() after expiry = %t, %v, want not found", ok, err)
+	}
+
+	if err := c.Set(ctx, want, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(ctx, "/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() after Delete() = %t, %v, want not found", ok, err)
+	}
+}
+
+func TestCache_invalid(t *testing.T) {
+	mock := &mockDynamoDB{items: map[string]map[string]types.AttributeValue{
+		"/foo": {
+			"name":    &types.AttributeValueMemberS{Value: "/foo"},
+			"expires": &types.AttributeValueMemberS{Value: "soon"},
+		},
+	}}
+	c := New(mock, "cache")
+	if _, _, err := c.Get(context.Background(), "/foo"); err == nil {
+		t.Error("Want error")
+	}
+}
+
+type mockDynamoDB struct {
+	items map[string]map[string]types.AttributeValue
+}
+
+func (m *mockDynamoDB) GetItem(ctx context.Context, input *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{
+		Item: m.items[str(input.Key["name"])],
+	}, nil
+}
+
+func (m *mockDynamoDB) PutItem(ctx context.Context, input *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	m.items[str(input.Item["name"])] = input.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockDynamoDB) DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	delete(m.items, str(input.Key["name"]))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
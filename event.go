@@ -0,0 +1,52 @@
+package ssm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// Invalidate removes the parameters with the given names from the cache, so
+// they are requested from SSM on the next read, including the versions read
+// with a label or version selector. Errors deleting from a shared cache are
+// logged.
+func (s *ParamStore) Invalidate(ctx context.Context, names ...string) {
+	s.forget(ctx, names)
+}
+
+// parameterChangeEvent is the part of a "Parameter Store Change" EventBridge
+// event that is needed to invalidate the parameter.
+type parameterChangeEvent struct {
+	Source     string `json:"source"`
+	DetailType string `json:"detail-type"`
+	Detail     struct {
+		Name      string `json:"name"`
+		Operation string `json:"operation"`
+	} `json:"detail"`
+}
+
+// InvalidateFromEvent invalidates the parameter in a "Parameter Store Change"
+// event sent by SSM to EventBridge, for example when the event is delivered to
+// an AWS Lambda function or read from an SQS queue:
+//
+//   func handler(ctx context.Context, event json.RawMessage) error {
+//       return ps.InvalidateFromEvent(ctx, event)
+//   }
+//
+// Other events are ignored. An error is returned if event is not valid JSON.
+func (s *ParamStore) InvalidateFromEvent(ctx context.Context, event []byte) error {
+	var e parameterChangeEvent
+	if err := json.Unmarshal(event, &e); err != nil {
+		return fmt.Errorf("decode event: %v", err)
+	}
+	if e.Source != "aws.ssm" || e.DetailType != "Parameter Store Change" || e.Detail.Name == "" {
+		return nil
+	}
+	s.log(ctx, slog.LevelDebug, "invalidate parameter",
+		slog.String("name", e.Detail.Name),
+		slog.String("operation", e.Detail.Operation),
+	)
+	s.Invalidate(ctx, e.Detail.Name)
+	return nil
+}
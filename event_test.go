@@ -0,0 +1,124 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_InvalidateFromEvent(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+		stringParam("/b", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+		B string `ssm:"b"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	mock.setParam(stringParam("/a", "2"))
+	mock.setParam(stringParam("/b", "2"))
+
+	events := []string{
+		`{"source":"aws.ec2","detail-type":"EC2 Instance State-change Notification","detail":{"name":"/b"}}`,
+		`{
+			"version": "0",
+			"id": "6a7e4feb-b491-4cf7-a9f1-bf3703497718",
+			"detail-type": "Parameter Store Change",
+			"source": "aws.ssm",
+			"account": "123456789012",
+			"time": "2017-05-22T16:43:48Z",
+			"region": "us-east-1",
+			"resources": ["arn:aws:ssm:us-east-1:123456789012:parameter/a"],
+			"detail": {
+				"operation": "Update",
+				"name": "/a",
+				"type": "String",
+				"description": "Sample Parameter"
+			}
+		}`,
+	}
+	for _, e := range events {
+		if err := ps.InvalidateFromEvent(context.Background(), []byte(e)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "A", value: "2"},
+		{path: "B", value: "1"},
+	})
+
+	if err := ps.InvalidateFromEvent(context.Background(), []byte("not json")); err == nil {
+		t.Error("Want error")
+	}
+}
+
+func TestParamStore_Invalidate(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Hour), WithStaleWhileRevalidate(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	mock.setParam(stringParam("/a", "2"))
+	ps.Invalidate(context.Background(), "/a")
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "2"}})
+}
+
+func TestParamStore_Invalidate_label(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			{Name: aws.String("/a"), Value: aws.String("1"), Type: types.ParameterTypeString, Version: 1},
+			{Name: aws.String("/a"), Value: aws.String("2"), Type: types.ParameterTypeString, Version: 2},
+		},
+		selectors: map[string]int64{"/a:prod": 1, "/a:canary": 1},
+	}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Hour), WithLabel("prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A      string `ssm:"a"`
+		Canary string `ssm:"a,label=canary"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "1"}, {path: "Canary", value: "1"}})
+
+	mock.selectors = map[string]int64{"/a:prod": 2, "/a:canary": 2}
+	ps.Invalidate(context.Background(), "/a")
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "2"}, {path: "Canary", value: "2"}})
+	if mock.calls != 2 {
+		t.Errorf("calls = %d, want 2", mock.calls)
+	}
+}
@@ -0,0 +1,81 @@
+package ssm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// WriteExample writes an example file listing every parameter read into
+// target, such as a .env.example to check in next to the code. Each
+// parameter is written as a key=value line with an empty value, with the
+// description from the desc= tag option as a comment:
+//
+//   type Config struct {
+//       Host  string   `ssm:"host,desc='Database host, without port'"`
+//       Peers []string `ssm:"peers"`
+//   }
+//
+// is written as:
+//
+//   # Database host, without port
+//   host=
+//   # StringList, comma separated
+//   peers=
+//
+// Keys are relative to the prefix, in the order the fields are declared. A
+// filled in copy of the file can be parsed into a map and written with
+// WriteMap. Parameters that are not under the prefix, such as absolute names
+// elsewhere and ARNs, are written commented out with their full name, as
+// WriteMap and FileProvider would read them under the prefix.
+func (s *ParamStore) WriteExample(w io.Writer, target interface{}) error {
+	ty := reflect.TypeOf(target)
+	for ty != nil && ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if ty == nil || ty.Kind() != reflect.Struct {
+		return fmt.Errorf("target is not a struct")
+	}
+	schema, err := s.schema(ty, s.prefix, nil)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	if s.prefix != "" {
+		fmt.Fprintf(bw, "# Parameters under %s\n\n", s.prefix)
+	}
+	for _, name := range declOrder(schema) {
+		indices := schema[name]
+		if desc, ok := s.fieldOption(ty, indices, "desc"); ok && desc != "" {
+			fmt.Fprintf(bw, "# %s\n", desc)
+		}
+		ft := structField(ty, indices[0]).Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		key, ok := strings.CutPrefix(name, s.prefix+"/")
+		if !ok {
+			fmt.Fprintf(bw, "# Not under the prefix, set separately\n")
+			key = "# " + name
+		}
+		if strings.HasSuffix(name, "/") {
+			fmt.Fprintf(bw, "# One parameter per key\n")
+			fmt.Fprintf(bw, "%s<key>=\n", key)
+			continue
+		}
+		if s.fieldFlag(ty, indices, "json") {
+			fmt.Fprintf(bw, "# JSON\n")
+		} else if s.fieldFlag(ty, indices, "kv") {
+			fmt.Fprintf(bw, "# key=value pairs, or a JSON object\n")
+		} else if sep, ok := s.fieldOption(ty, indices, "sep"); ok {
+			fmt.Fprintf(bw, "# String, separated by %s\n", sep)
+		} else if ft.Kind() == reflect.Slice && !isText(ft) && !isJSON(ft) {
+			fmt.Fprintf(bw, "# StringList, comma separated\n")
+		}
+		fmt.Fprintf(bw, "%s=\n", key)
+	}
+	return bw.Flush()
+}
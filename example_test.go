@@ -0,0 +1,95 @@
+package ssm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParamStore_WriteExample(t *testing.T) {
+	type config struct {
+		Host  string   `ssm:"host,desc='Database host, without port'"`
+		Port  string   `ssm:"port"`
+		Peers []string `ssm:"peers,desc=Other nodes"`
+		Auth  struct {
+			Token *string `ssm:"token,desc=API token"`
+		} `ssm:"auth"`
+		Endpoints map[string][]string `ssm:"endpoints"`
+		Weights   map[string]int      `ssm:"weights,json"`
+		Ignored   string
+	}
+
+	ps, err := NewParamStore(WithClient(&mockSSM{}), WithPrefix("dev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := ps.WriteExample(&buf, &config{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `# Parameters under /dev
+
+# Database host, without port
+host=
+port=
+# Other nodes
+# StringList, comma separated
+peers=
+# API token
+auth/token=
+# One parameter per key
+endpoints/<key>=
+# JSON
+weights=
+`
+	if diff := cmp.Diff(buf.String(), want); diff != "" {
+		t.Errorf("WriteExample() (-got +want)\n%s", diff)
+	}
+}
+
+func TestParamStore_WriteExample_outsidePrefix(t *testing.T) {
+	type config struct {
+		Host   string            `ssm:"host"`
+		Shared string            `ssm:"/shared/region,desc=AWS region"`
+		Key    string            `ssm:"arn:aws:ssm:eu-west-1:123456789012:parameter/keys/api"`
+		Flags  map[string]string `ssm:"/shared/flags"`
+	}
+
+	ps, err := NewParamStore(WithClient(&mockSSM{}), WithPrefix("dev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := ps.WriteExample(&buf, &config{}); err != nil {
+		t.Fatal(err)
+	}
+	want := `# Parameters under /dev
+
+host=
+# AWS region
+# Not under the prefix, set separately
+# /shared/region=
+# Not under the prefix, set separately
+# arn:aws:ssm:eu-west-1:123456789012:parameter/keys/api=
+# Not under the prefix, set separately
+# One parameter per key
+# /shared/flags/<key>=
+`
+	if diff := cmp.Diff(buf.String(), want); diff != "" {
+		t.Errorf("WriteExample() (-got +want)\n%s", diff)
+	}
+}
+
+func TestParamStore_WriteExample_notStruct(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ps.WriteExample(&buf, "foo"); err == nil {
+		t.Fatal("Want error")
+	}
+}
@@ -0,0 +1,326 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+)
+
+// maxNames is the maximum number of names that can be passed to
+// GetParameters.
+const maxNames = 10
+
+// fetch gets the parameters with the given names. The names are split into
+// batches to stay within the limit of GetParameters. The parameters directly
+// under the paths of map fields are fetched with GetParametersByPath. If
+// WithResolver was passed, the names are looked up from the resolver instead.
+func (s *ParamStore) fetch(ctx context.Context, names []string) ([]types.Parameter, error) {
+	paths, names := splitPaths(names)
+	var unread []string
+	params, err := s.fetchPaths(ctx, paths)
+	if err != nil && budgetExceeded(ctx) {
+		unread = paths
+	} else if err != nil {
+		return nil, err
+	}
+	if s.resolver != nil && len(names) > 0 {
+		found, err := s.resolver.Lookup(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		return append(params, found...), nil
+	}
+	prefix := s.readPrefix(ctx)
+	tuned := s.tuner != nil && !s.pathFetch && len(names) > 0
+	usePath := s.pathFetch
+	if tuned && prefix != "" {
+		usePath = s.tuner.usePath()
+	}
+	start := time.Now()
+	if usePath && prefix != "" {
+		var found []types.Parameter
+		found, names, err = s.fetchPath(ctx, prefix, names)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, found...)
+	}
+
+	var decrypted, encrypted []string
+	for _, name := range names {
+		if s.decrypts(ctx, name) {
+			decrypted = append(decrypted, name)
+		} else {
+			encrypted = append(encrypted, name)
+		}
+	}
+	concurrency, size := s.maxConcurrency, maxNames
+	if tuned {
+		concurrency, size = s.tuner.batching(len(names))
+	}
+	batches := appendBatches(nil, decrypted, true, size)
+	batches = appendBatches(batches, encrypted, false, size)
+
+	results, err := s.fetchBatches(ctx, batches, concurrency)
+	if _, ok := unreadNames(err); err != nil && !ok {
+		return nil, err
+	}
+	if tuned && err == nil && len(unread) == 0 {
+		s.tuner.observe(usePath && prefix != "", time.Since(start))
+	}
+	for i, r := range results {
+		params = append(params, matchARNs(batches[i].names, matchSelectors(r))...)
+	}
+	if len(unread) > 0 {
+		perr, _ := err.(PartialError)
+		perr.names = append(unread, perr.names...)
+		sort.Strings(perr.names)
+		return params, perr
+	}
+	return params, err
+}
+
+// A batch is a set of names fetched with a single call to GetParameters.
+type batch struct {
+	names   []string
+	decrypt bool
+}
+
+// appendBatches splits the names into batches of up to size names.
+func appendBatches(batches []batch, names []string, decrypt bool, size int) []batch {
+	for len(names) > 0 {
+		n := len(names)
+		if n > size {
+			n = size
+		}
+		batches = append(batches, batch{names: names[:n], decrypt: decrypt})
+		names = names[n:]
+	}
+	return batches
+}
+
+// fetchBatches gets each batch of names with GetParameters. Up to
+// concurrency batches are fetched at the same time. The first error
+// cancels the remaining requests.
+//
+// If WithDecryptionFallback was passed, the results are returned along with
+// a DecryptionError if only SecureString parameters could not be fetched.
+// If the read budget set with WithReadBudget is exhausted, the results of the
+// batches that completed are returned along with a PartialError naming the
+// parameters of the other batches.
+func (s *ParamStore) fetchBatches(ctx context.Context, batches []batch, concurrency int) ([][]types.Parameter, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+
+		mu      sync.Mutex
+		decrypt DecryptionError
+		partial PartialError
+	)
+	results := make([][]types.Parameter, len(batches))
+	for i, b := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			if budgetExceeded(ctx) {
+				mu.Lock()
+				for _, b := range batches[i:] {
+					partial.names = append(partial.names, b.names...)
+				}
+				mu.Unlock()
+			}
+			break
+		}
+		wg.Add(1)
+		go func(i int, b batch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			params, err := s.getParameters(ctx, b)
+			if err != nil && b.decrypt && s.decryptFallback && ctx.Err() == nil {
+				var failed []string
+				params, failed, err = s.fallback(ctx, b, err)
+				if len(failed) > 0 {
+					mu.Lock()
+					decrypt.names = append(decrypt.names, failed...)
+					decrypt.err = err
+					mu.Unlock()
+					err = nil
+				}
+			}
+			if err != nil && budgetExceeded(ctx) {
+				mu.Lock()
+				partial.names = append(partial.names, b.names...)
+				mu.Unlock()
+				return
+			}
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = params
+		}(i, b)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if len(partial.names) > 0 {
+		partial.names = append(partial.names, decrypt.names...)
+		sort.Strings(partial.names)
+		return results, partial
+	}
+	if err := ctx.Err(); err != nil && !budgetExceeded(ctx) {
+		// Exhausting the budget after every batch completed is not an
+		// error.
+		return nil, err
+	}
+	if len(decrypt.names) > 0 {
+		sort.Strings(decrypt.names)
+		return results, decrypt
+	}
+	return results, nil
+}
+
+// getParameters gets the batch of names with GetParameters, or with
+// GetParameter if the batch has a single name.
+func (s *ParamStore) getParameters(ctx context.Context, b batch) ([]types.Parameter, error) {
+	if b.decrypt && s.decryptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.decryptTimeout)
+		defer cancel()
+	}
+	if len(b.names) == 1 {
+		return s.getParameter(ctx, b.names[0], b.decrypt)
+	}
+	input := &ssm.GetParametersInput{
+		Names:          b.names,
+		WithDecryption: aws.Bool(b.decrypt),
+	}
+	var resp *ssm.GetParametersOutput
+	err := s.send(ctx, func() (err error) {
+		resp, err = s.cli.GetParameters(ctx, input, s.clientOptions()...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Parameters, nil
+}
+
+// getParameter gets a single parameter with GetParameter, which supports
+// selectors on every call path and reports why a parameter was not found.
+// Like with GetParameters, a parameter that does not exist is not returned.
+func (s *ParamStore) getParameter(ctx context.Context, name string, decrypt bool) ([]types.Parameter, error) {
+	input := &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(decrypt),
+	}
+	var resp *ssm.GetParameterOutput
+	err := s.send(ctx, func() (err error) {
+		resp, err = s.cli.GetParameter(ctx, input, s.clientOptions()...)
+		return err
+	})
+	var aerr smithy.APIError
+	if errors.As(err, &aerr) && notFoundCodes[aerr.ErrorCode()] {
+		s.log(ctx, slog.LevelDebug, "parameter not found",
+			slog.String("name", name),
+			slog.String("error", aerr.ErrorMessage()),
+		)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.Parameter == nil {
+		return nil, nil
+	}
+	return []types.Parameter{*resp.Parameter}, nil
+}
+
+// notFoundCodes are the error codes of GetParameter for a parameter, version
+// or label that does not exist.
+var notFoundCodes = map[string]bool{
+	"ParameterNotFound":        true,
+	"ParameterVersionNotFound": true,
+}
+
+// fetchPath gets the parameters with the given names that are under the
+// prefix with GetParametersByPath. The names not under the prefix are
+// returned.
+func (s *ParamStore) fetchPath(ctx context.Context, prefix string, names []string) ([]types.Parameter, []string, error) {
+	want := make(map[string]bool)
+	var rest []string
+	for _, name := range names {
+		_, selector := splitSelector(name)
+		if strings.HasPrefix(name, prefix+"/") && s.decrypts(ctx, name) == s.decrypt && selector == "" {
+			want[name] = true
+		} else {
+			rest = append(rest, name)
+		}
+	}
+	if len(want) == 0 {
+		return nil, rest, nil
+	}
+
+	all, err := s.getPath(ctx, prefix, s.decrypt)
+	if err != nil {
+		return nil, nil, err
+	}
+	var params []types.Parameter
+	for _, p := range all {
+		if want[*p.Name] {
+			params = append(params, p)
+		}
+	}
+	return params, rest, nil
+}
+
+// getPath gets all parameters under path with GetParametersByPath.
+func (s *ParamStore) getPath(ctx context.Context, path string, decrypt bool) ([]types.Parameter, error) {
+	var params []types.Parameter
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(decrypt),
+	}
+	for {
+		var resp *ssm.GetParametersByPathOutput
+		err := s.send(ctx, func() (err error) {
+			resp, err = s.cli.GetParametersByPath(ctx, input, s.clientOptions()...)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, resp.Parameters...)
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+	return params, nil
+}
@@ -0,0 +1,249 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_Read_batch(t *testing.T) {
+	ty, params, want := largeConfig(25)
+	mock := &mockSSM{params: params}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	val := reflect.New(ty)
+	if err := ps.Read(context.Background(), val.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	check(t, val.Elem().Interface(), want)
+	if mock.calls != 3 {
+		t.Errorf("GetParameters called %d times, want 3", mock.calls)
+	}
+	if mock.maxInFlight != 1 {
+		t.Errorf("Max concurrent calls = %d, want 1", mock.maxInFlight)
+	}
+}
+
+func TestParamStore_Read_single(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "db.example.com"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "Host", value: "db.example.com"}})
+	if mock.singleCalls != 1 {
+		t.Errorf("GetParameter called %d times, want 1", mock.singleCalls)
+	}
+
+	var missing struct {
+		Port string `ssm:"port"`
+	}
+	if _, ok := ps.Read(context.Background(), &missing).(NotFoundError); !ok {
+		t.Errorf("Read() err is not NotFoundError")
+	}
+}
+
+func TestParamStore_Read_maxConcurrency(t *testing.T) {
+	ty, params, want := largeConfig(55)
+	mock := &mockSSM{params: params, delay: 10 * time.Millisecond}
+	ps, err := NewParamStore(WithClient(mock), WithMaxConcurrency(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	val := reflect.New(ty)
+	if err := ps.Read(context.Background(), val.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	check(t, val.Elem().Interface(), want)
+	if mock.calls != 6 {
+		t.Errorf("GetParameters called %d times, want 6", mock.calls)
+	}
+	if mock.maxInFlight != 3 {
+		t.Errorf("Max concurrent calls = %d, want 3", mock.maxInFlight)
+	}
+}
+
+func TestParamStore_Read_maxConcurrencyError(t *testing.T) {
+	ty, params, _ := largeConfig(55)
+	mock := &mockSSM{params: params, err: fmt.Errorf("error")}
+	ps, err := NewParamStore(WithClient(mock), WithMaxConcurrency(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ps.Read(context.Background(), reflect.New(ty).Interface()); err == nil {
+		t.Fatal("Want error")
+	}
+	if mock.calls == 6 {
+		t.Errorf("GetParameters called %d times, want remaining calls to be canceled", mock.calls)
+	}
+}
+
+func TestParamStore_Read_canceled(t *testing.T) {
+	ty, params, _ := largeConfig(55)
+	mock := &mockSSM{params: params, delay: time.Second}
+	ps, err := NewParamStore(WithClient(mock), WithMaxConcurrency(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := ps.Read(ctx, reflect.New(ty).Interface()); err == nil {
+		t.Fatal("Want error")
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Read took %s, want to return when canceled", d)
+	}
+}
+
+func TestParamStore_Read_pathFetch(t *testing.T) {
+	cfg := struct {
+		A  string `ssm:"a"`
+		B  string `ssm:"b"`
+		DB struct {
+			User string `ssm:"user"`
+			Pass string `ssm:"pass"`
+		} `ssm:"db"`
+		E string `ssm:"e"`
+	}{}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/dev/a", "1"),
+		stringParam("/dev/b", "2"),
+		stringParam("/dev/db/user", "3"),
+		secureStringParam("/dev/db/pass", "4"),
+		stringParam("/dev/e", "5"),
+		stringParam("/dev/unused", "6"),
+		stringParam("/prod/a", "7"),
+	}}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPrefix("dev"),
+		WithPathFetch(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "A", value: "1"},
+		{path: "B", value: "2"},
+		{path: "DB.User", value: "3"},
+		{path: "DB.Pass", value: "4"},
+		{path: "E", value: "5"},
+	})
+	if mock.calls != 0 {
+		t.Errorf("GetParameters called %d times, want 0", mock.calls)
+	}
+	// 6 parameters under /dev, 2 per page
+	if mock.pathCalls != 3 {
+		t.Errorf("GetParametersByPath called %d times, want 3", mock.pathCalls)
+	}
+}
+
+func TestParamStore_Read_pathFetchNotFound(t *testing.T) {
+	cfg := struct {
+		A string `ssm:"a"`
+	}{}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/dev/b", "1"),
+	}}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPrefix("dev"),
+		WithPathFetch(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ps.Read(context.Background(), &cfg)
+	if _, ok := err.(NotFoundError); !ok {
+		t.Errorf("Read() err = %v, want NotFoundError", err)
+	}
+}
+
+func TestParamStore_Read_pathFetchNoPrefix(t *testing.T) {
+	cfg := struct {
+		A string `ssm:"a"`
+	}{}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPathFetch(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "1"}})
+	if mock.pathCalls != 0 {
+		t.Errorf("GetParametersByPath called %d times, want 0", mock.pathCalls)
+	}
+}
+
+func TestParamStore_Read_pathFetchError(t *testing.T) {
+	cfg := struct {
+		A string `ssm:"a"`
+	}{}
+
+	mock := &mockSSM{err: fmt.Errorf("error")}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPrefix("dev"),
+		WithPathFetch(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err == nil {
+		t.Error("Want error")
+	}
+}
+
+// largeConfig creates a struct type with n string fields, along with matching
+// parameters and expected values.
+func largeConfig(n int) (reflect.Type, []types.Parameter, []value) {
+	var (
+		fields []reflect.StructField
+		params []types.Parameter
+		want   []value
+	)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("Field%d", i)
+		fields = append(fields, reflect.StructField{
+			Name: name,
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(fmt.Sprintf(`ssm:"field_%d"`, i)),
+		})
+		params = append(params, stringParam(fmt.Sprintf("/field_%d", i), fmt.Sprint(i)))
+		want = append(want, value{path: name, value: fmt.Sprint(i)})
+	}
+	return reflect.StructOf(fields), params, want
+}
@@ -0,0 +1,99 @@
+package ssm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A flight is a fetch shared by concurrent reads of the same parameters.
+type flight struct {
+	done    chan struct{}
+	params  []types.Parameter
+	err     error
+	sources *sourceRecorder
+
+	// waiters is the number of calls waiting for the fetch. The fetch is
+	// canceled once every call stopped waiting.
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// fetchShared gets the parameters with the given names like fetchCached.
+// Concurrent calls for the same names share a single fetch, so reading the
+// same target from many goroutines at once only requests the parameters
+// once.
+//
+// The fetch is not canceled with the context of the call that started it, so
+// the other calls do not fail if it is. Each call stops waiting when its own
+// context is done, and the fetch is canceled when no call is waiting for it
+// anymore. The fetch is limited by the read budget of the call that started
+// it, if set.
+func (s *ParamStore) fetchShared(ctx context.Context, names []string) ([]types.Parameter, error) {
+	// Reads with and without decryption, or with different TTLs, of the
+	// same names do not share a fetch.
+	ttls := readOptionsFrom(ctx).ttls
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = s.cacheKey(ctx, name)
+		if ttl, ok := ttls[name]; ok {
+			keys[i] += "@" + ttl.String()
+		}
+	}
+	key := strings.Join(keys, "\x00")
+	if isBypassCache(ctx) {
+		key = "bypass\x00" + key
+	}
+
+	s.mu.Lock()
+	f, ok := s.flights[key]
+	if !ok {
+		fctx, cancel := budgetContext(context.WithoutCancel(ctx), ctx)
+		f = &flight{
+			done:    make(chan struct{}),
+			sources: &sourceRecorder{sources: make(map[string]Source)},
+			cancel:  cancel,
+		}
+		s.flights[key] = f
+		go s.fly(fctx, key, f, names)
+	}
+	f.waiters++
+	s.mu.Unlock()
+
+	select {
+	case <-f.done:
+		mergeSources(ctx, f.sources)
+		return f.params, f.err
+	case <-ctx.Done():
+		s.mu.Lock()
+		f.waiters--
+		last := f.waiters == 0
+		if last {
+			// Later calls start a new fetch.
+			f.cancel()
+			if s.flights[key] == f {
+				delete(s.flights, key)
+			}
+		}
+		s.mu.Unlock()
+		if last {
+			// No requests are left running once the last call returns.
+			<-f.done
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// fly runs the fetch of the flight.
+func (s *ParamStore) fly(ctx context.Context, key string, f *flight, names []string) {
+	defer f.cancel()
+	f.params, f.err = s.fetchCached(withRecorder(ctx, f.sources), names)
+
+	s.mu.Lock()
+	if s.flights[key] == f {
+		delete(s.flights, key)
+	}
+	s.mu.Unlock()
+	close(f.done)
+}
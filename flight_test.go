@@ -0,0 +1,131 @@
+package ssm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_Read_concurrent(t *testing.T) {
+	type config struct {
+		A string `ssm:"a"`
+		B string `ssm:"b"`
+	}
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/a", "1"),
+			stringParam("/b", "2"),
+		},
+		delay: 100 * time.Millisecond,
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	cfgs := make([]config, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = ps.Read(context.Background(), &cfgs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		check(t, cfgs[i], []value{
+			{path: "A", value: "1"},
+			{path: "B", value: "2"},
+		})
+	}
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+
+	// Not shared once done
+	if err := ps.Read(context.Background(), &cfgs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if mock.calls != 2 {
+		t.Errorf("GetParameters called %d times, want 2", mock.calls)
+	}
+}
+
+func TestParamStore_Read_concurrentCanceled(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{stringParam("/a", "1")},
+		delay:  time.Second,
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	leader, cancelLeader := context.WithCancel(context.Background())
+	defer cancelLeader()
+	go func() { _ = ps.Read(leader, &cfg) }()
+	time.Sleep(10 * time.Millisecond)
+
+	// Waiting for the shared fetch stops when the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var other struct {
+		A string `ssm:"a"`
+	}
+	if err := ps.Read(ctx, &other); err == nil {
+		t.Fatal("Want error")
+	}
+}
+
+func TestParamStore_Read_concurrentLeaderCanceled(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{stringParam("/a", "1")},
+		delay:  100 * time.Millisecond,
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		A string `ssm:"a"`
+	}
+	leader, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		var cfg config
+		leaderErr <- ps.Read(leader, &cfg)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	var cfg config
+	followerErr := make(chan error)
+	go func() { followerErr <- ps.Read(context.Background(), &cfg) }()
+	time.Sleep(10 * time.Millisecond)
+
+	// The follower keeps waiting for the shared fetch
+	cancelLeader()
+	if err := <-leaderErr; err == nil {
+		t.Error("Want error for the leader")
+	}
+	if err := <-followerErr; err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "1"}})
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+}
@@ -0,0 +1,180 @@
+package ssm
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// formats are the validators of the format= tag option, by name.
+var formats = map[string]func(value string) bool{
+	"hostname": isHostname,
+	"email":    isEmail,
+	"arn":      isValidARN,
+	"s3uri":    isS3URI,
+	"semver":   semverPattern.MatchString,
+}
+
+// A ValidationError is returned when parameter values do not match the format
+// set with the format= tag option, fields with the secure tag option are read
+// from parameters that are not SecureString, or names violate the naming
+// policy set with WithNamingPolicy. It lists every invalid parameter. The
+// values are not included, as they may be secret.
+type ValidationError struct {
+	invalid []invalidParam
+}
+
+type invalidParam struct {
+	name   string
+	reason string
+}
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e.invalid))
+	for i, p := range e.invalid {
+		msgs[i] = p.name + ": " + p.reason
+	}
+	return "invalid: " + strings.Join(msgs, ", ")
+}
+
+// Names returns the names of the parameters with invalid values.
+func (e ValidationError) Names() []string {
+	names := make([]string, len(e.invalid))
+	for i, p := range e.invalid {
+		names[i] = p.name
+	}
+	return names
+}
+
+// validate checks the values of the parameters read into fields with the
+// format= tag option:
+//
+//   Host   string `ssm:"host,format=hostname"`
+//   Bucket string `ssm:"bucket,format=s3uri"`
+//
+// Each value of a StringList parameter is checked separately. An error is
+// returned if a field has an unknown format.
+//
+// Parameters read into fields with the secure tag option must be
+// SecureString, to catch credentials stored as plain text:
+//
+//   Password string `ssm:"password,secure"`
+//
+// The minversion= tag option requires the version of the parameter to be at
+// least the given version, to check that a rotation or migration has
+// happened:
+//
+//   Key string `ssm:"key,minversion=4"`
+func (s *ParamStore) validate(ty reflect.Type, schema map[string][][]int, params []types.Parameter) error {
+	var invalid []invalidParam
+	paths, _ := splitPaths(sortedNames(schema))
+	for _, p := range params {
+		indices, ok := schema[*p.Name]
+		for _, path := range paths {
+			if !ok && isChild(path, *p.Name) {
+				// The values of a map field
+				indices, ok = schema[path], true
+			}
+		}
+		if !ok || p.Value == nil {
+			continue
+		}
+		if p.Type != types.ParameterTypeSecureString && s.fieldFlag(ty, indices, "secure") {
+			reason := fmt.Sprintf("not a SecureString (%s)", p.Type)
+			invalid = append(invalid, invalidParam{name: *p.Name, reason: reason})
+			continue
+		}
+		if v, ok := s.fieldOption(ty, indices, "minversion"); ok {
+			min, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || min < 1 {
+				return fmt.Errorf("%s: invalid minversion %q", *p.Name, v)
+			}
+			if p.Version < min {
+				reason := fmt.Sprintf("version %d is below minversion %d", p.Version, min)
+				invalid = append(invalid, invalidParam{name: *p.Name, reason: reason})
+				continue
+			}
+		}
+		format, ok := s.fieldOption(ty, indices, "format")
+		if !ok {
+			continue
+		}
+		valid, ok := formats[format]
+		if !ok {
+			return fmt.Errorf("%s: unknown format %q", *p.Name, format)
+		}
+		values := []string{*p.Value}
+		if p.Type == types.ParameterTypeStringList {
+			values = strings.Split(*p.Value, ",")
+		}
+		for _, v := range values {
+			if !valid(v) {
+				invalid = append(invalid, invalidParam{name: *p.Name, reason: "not a valid " + format})
+				break
+			}
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Slice(invalid, func(i, j int) bool {
+		return invalid[i].name < invalid[j].name
+	})
+	return ValidationError{invalid: invalid}
+}
+
+// isHostname reports whether v is a valid hostname as defined in RFC 1123.
+func isHostname(v string) bool {
+	if v == "" || len(v) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(v, ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// isEmail reports whether v is an email address, without a display name.
+func isEmail(v string) bool {
+	addr, err := mail.ParseAddress(v)
+	return err == nil && addr.Address == v
+}
+
+// isValidARN reports whether v is an ARN with a partition, service and
+// resource.
+func isValidARN(v string) bool {
+	a, err := arn.Parse(v)
+	return err == nil && a.Partition != "" && a.Service != "" && a.Resource != ""
+}
+
+// isS3URI reports whether v is an S3 URI, such as s3://bucket/key, with a
+// valid bucket name.
+func isS3URI(v string) bool {
+	if !strings.HasPrefix(v, "s3://") {
+		return false
+	}
+	bucket := strings.TrimPrefix(v, "s3://")
+	if i := strings.Index(bucket, "/"); i >= 0 {
+		bucket = bucket[:i]
+	}
+	return bucketName.MatchString(bucket) && !strings.Contains(bucket, "..")
+}
+
+var bucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// semverPattern matches a semantic version, as defined by
+// https://semver.org. A leading v is allowed.
+var semverPattern = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
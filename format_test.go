@@ -0,0 +1,185 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		valid  []string
+		bad    []string
+	}{
+		{
+			format: "hostname",
+			valid:  []string{"localhost", "db.example.com", "a-1.b2"},
+			bad:    []string{"", "-db.example.com", "db..example.com", "db_1.example.com", "db.example.com:5432"},
+		},
+		{
+			format: "email",
+			valid:  []string{"alice@example.com"},
+			bad:    []string{"alice", "Alice <alice@example.com>", "@example.com"},
+		},
+		{
+			format: "arn",
+			valid:  []string{"arn:aws:ssm:eu-west-1:123456789012:parameter/db/host", "arn:aws:s3:::bucket"},
+			bad:    []string{"arn:aws:ssm", "arn:aws::eu-west-1:123:x", "ssm:eu-west-1"},
+		},
+		{
+			format: "s3uri",
+			valid:  []string{"s3://bucket", "s3://my.bucket/path/to/key"},
+			bad:    []string{"bucket/key", "s3://Bucket/key", "s3://ab", "s3://a..b/key", "https://bucket.s3.amazonaws.com"},
+		},
+		{
+			format: "semver",
+			valid:  []string{"1.2.3", "v0.1.0", "1.0.0-rc.1+build.5"},
+			bad:    []string{"1.2", "01.2.3", "1.2.3-", "latest"},
+		},
+	}
+	for _, tt := range tests {
+		valid := formats[tt.format]
+		for _, v := range tt.valid {
+			if !valid(v) {
+				t.Errorf("%s: %q is not valid", tt.format, v)
+			}
+		}
+		for _, v := range tt.bad {
+			if valid(v) {
+				t.Errorf("%s: %q is valid", tt.format, v)
+			}
+		}
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "db_1.example.com"),
+		stringParam("/email", "alice@example.com"),
+		stringListParam("/versions", "1.0.0,2.0"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host     string   `ssm:"host,format=hostname"`
+		Email    string   `ssm:"email,format=email"`
+		Versions []string `ssm:"versions,format=semver"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	verr, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("Read() err = %v, want ValidationError", err)
+	}
+	if diff := cmp.Diff(verr.Names(), []string{"/host", "/versions"}); diff != "" {
+		t.Errorf("Names() (-got +want)\n%s", diff)
+	}
+	want := "invalid: /host: not a valid hostname, /versions: not a valid semver"
+	if got := verr.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "db.example.com"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host,format=uuid"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err == nil {
+		t.Errorf("Read() err = nil, want error")
+	}
+}
+
+func TestSecure(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		secureStringParam("/password", "hunter2"),
+		stringParam("/token", "secret"),
+		stringParam("/keys/a", "key"),
+		secureStringParam("/keys/b", "key"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Password string            `ssm:"password,secure"`
+		Token    string            `ssm:"token,secure"`
+		Keys     map[string]string `ssm:"keys,secure"`
+		Optional string            `ssm:"optional,secure,optional"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	verr, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("Read() err = %v, want ValidationError", err)
+	}
+	want := "invalid: /keys/a: not a SecureString (String), /token: not a SecureString (String)"
+	if got := verr.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestMinVersion(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/key", "old"))
+	mock.setParam(stringParam("/key", "new"))
+	mock.setParam(stringParam("/token", "old"))
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Key   string `ssm:"key,minversion=2"`
+		Token string `ssm:"token,minversion=2"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	verr, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("Read() err = %v, want ValidationError", err)
+	}
+	want := "invalid: /token: version 1 is below minversion 2"
+	if got := verr.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	mock.setParam(stringParam("/token", "new"))
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Key != "new" || cfg.Token != "new" {
+		t.Errorf("Read() = %+v", cfg)
+	}
+}
+
+func TestMinVersion_invalid(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/key", "value"))
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Key string `ssm:"key,minversion=latest"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	if err == nil {
+		t.Fatal("Want error")
+	}
+	if _, ok := err.(ValidationError); ok {
+		t.Errorf("Read() err = %v, want error for the tag option", err)
+	}
+}
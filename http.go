@@ -0,0 +1,76 @@
+package ssm
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// InvalidateHandler returns an http.Handler that removes the parameters of
+// target from the cache and reads them again into target. This allows a deploy
+// pipeline or event handler to push updates, rather than waiting for cached
+// values to expire.
+//
+// The handler only accepts POST requests. It responds with 204 No Content if
+// the read succeeded.
+//
+// The parameters are read into a copy of target, which replaces target while
+// mu is held, so the application can keep reading target while the request is
+// handled as long as it holds mu, or the read lock of a sync.RWMutex:
+//
+//   var mu sync.RWMutex
+//   http.Handle("/invalidate", ps.InvalidateHandler(&cfg, &mu))
+//   ...
+//   mu.RLock()
+//   host := cfg.Host
+//   mu.RUnlock()
+//
+// target is not modified if the read fails.
+func (s *ParamStore) InvalidateHandler(target interface{}, mu sync.Locker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if err := s.invalidate(r.Context(), target, mu); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// invalidate removes the parameters of target from the cache and reads them
+// again into a copy of target, which then replaces target while mu is held.
+func (s *ParamStore) invalidate(ctx context.Context, target interface{}, mu sync.Locker) error {
+	val, err := targetValue(target)
+	if err != nil {
+		return err
+	}
+	schema, err := s.schema(val.Type(), s.prefix, nil)
+	if err != nil {
+		return err
+	}
+	s.forget(ctx, sortedNames(schema))
+
+	next := reflect.New(val.Type())
+	mu.Lock()
+	next.Elem().Set(val)
+	mu.Unlock()
+	// The pointers shared with target are replaced, so reading does not
+	// modify target.
+	for _, indices := range schema {
+		for _, index := range indices {
+			detach(next.Elem(), index)
+		}
+	}
+	if err := s.Read(ctx, next.Interface()); err != nil {
+		return err
+	}
+	mu.Lock()
+	val.Set(next.Elem())
+	mu.Unlock()
+	return nil
+}
@@ -0,0 +1,120 @@
+package ssm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_InvalidateHandler(t *testing.T) {
+	cache := newTestCache()
+	cache.entries["/foo"] = CachedParameter{Name: "/foo", Type: "String", Value: "old"}
+	cache.entries["/other"] = CachedParameter{Name: "/other", Type: "String", Value: "other"}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/foo", "new"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithSharedCache(cache, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Foo string `ssm:"foo"`
+	}
+	h := ps.InvalidateHandler(&cfg, &sync.Mutex{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invalidate", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body)
+	}
+	check(t, cfg, []value{{path: "Foo", value: "new"}})
+	if got := cache.entries["/foo"].Value; got != "new" {
+		t.Errorf("Cached value = %q, want %q", got, "new")
+	}
+	if _, ok := cache.entries["/other"]; !ok {
+		t.Errorf("Unrelated parameter was removed from cache")
+	}
+}
+
+func TestParamStore_InvalidateHandler_method(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct{}
+	rec := httptest.NewRecorder()
+	ps.InvalidateHandler(&cfg, &sync.Mutex{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/invalidate", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestParamStore_InvalidateHandler_readError(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Foo string `ssm:"foo"`
+	}
+	rec := httptest.NewRecorder()
+	ps.InvalidateHandler(&cfg, &sync.Mutex{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invalidate", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestParamStore_InvalidateHandler_concurrent(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/db/host", "old"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		DB *struct {
+			Host string `ssm:"host"`
+		} `ssm:"db"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	prev := cfg.DB
+
+	var mu sync.RWMutex
+	h := ps.InvalidateHandler(&cfg, &mu)
+	mock.setParam(stringParam("/db/host", "new"))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			mu.RLock()
+			_ = cfg.DB.Host
+			mu.RUnlock()
+		}
+	}()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/invalidate", nil))
+	<-done
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body)
+	}
+	if cfg.DB.Host != "new" {
+		t.Errorf("Host = %q, want %q", cfg.DB.Host, "new")
+	}
+	if prev.Host != "old" {
+		t.Errorf("Previous config was modified: Host = %q", prev.Host)
+	}
+}
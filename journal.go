@@ -0,0 +1,193 @@
+package ssm
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A Snapshot is a config observed by a ParamStore, as recorded in a Journal.
+type Snapshot struct {
+	// Time is when the config was first read.
+	Time time.Time `json:"time"`
+	// Type and Prefix identify the config: the type of the struct read, such
+	// as main.Config, and the prefix it was read from.
+	Type   string `json:"type"`
+	Prefix string `json:"prefix"`
+	// Fingerprint is a hash of the names and versions of the parameters,
+	// computed in the same way as Summary.VersionsHash.
+	Fingerprint string `json:"fingerprint"`
+	// Versions are the versions of the parameters read, by name.
+	Versions map[string]int64 `json:"versions"`
+}
+
+// A Journal is an append-only file of the configs a ParamStore has read, to
+// find out which config was active at a given time, for example after an
+// incident. Each line of the file is a Snapshot encoded as JSON.
+//
+// Each struct type and prefix read is a separate config, with its own
+// snapshots.
+type Journal struct {
+	path string
+
+	mu sync.Mutex
+	// last holds the fingerprint of the last snapshot written, by config.
+	last map[string]string
+}
+
+// NewJournal creates a Journal that appends to the file at path. The file is
+// created when the first snapshot is written.
+func NewJournal(path string) *Journal {
+	return &Journal{path: path, last: make(map[string]string)}
+}
+
+// WithJournal records the config in the journal each time a read finds that
+// a parameter changed, including reads by StartRefresh. The first read after
+// the ParamStore was created is always recorded. The values of the parameters
+// are not recorded.
+//
+// Errors writing the journal are logged, and do not fail the read. A journal
+// should only be used by a single ParamStore.
+func WithJournal(j *Journal) Option {
+	return func(s *ParamStore) {
+		s.journal = j
+	}
+}
+
+// record appends a snapshot of the params read into t from prefix to the
+// journal of s, if set and the params changed since the last snapshot of the
+// config.
+func (s *ParamStore) record(ctx context.Context, t reflect.Type, prefix string, params []types.Parameter) {
+	if s.journal == nil {
+		return
+	}
+	versions := make(map[string]int64, len(params))
+	for _, p := range params {
+		versions[*p.Name] = p.Version
+	}
+	snap := Snapshot{
+		Time:        s.now().UTC(),
+		Type:        t.String(),
+		Prefix:      prefix,
+		Fingerprint: versionsHash(versions),
+		Versions:    versions,
+	}
+	if err := s.journal.append(snap); err != nil {
+		s.log(ctx, slog.LevelWarn, "write journal", slog.String("error", err.Error()))
+	}
+}
+
+func (j *Journal) append(snap Snapshot) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	key := snap.config()
+	if snap.Fingerprint == j.last[key] {
+		return nil
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	// Written with a single call, so a concurrent reader sees whole lines.
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	j.last[key] = snap.Fingerprint
+	return nil
+}
+
+// config returns the key of the config of the snapshot.
+func (snap Snapshot) config() string {
+	return snap.Type + " " + snap.Prefix
+}
+
+// Snapshots returns the snapshots in the journal, oldest first. A journal
+// that has not been written yet has no snapshots.
+func (j *Journal) Snapshots() ([]Snapshot, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 16<<20)
+	for n := 1; sc.Scan(); n++ {
+		var snap Snapshot
+		if err := json.Unmarshal(sc.Bytes(), &snap); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", j.path, n, err)
+		}
+		snaps = append(snaps, snap)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(snaps, func(a, b int) bool {
+		return snaps[a].Time.Before(snaps[b].Time)
+	})
+	return snaps, nil
+}
+
+// ActiveAt returns the snapshots of the configs that were active at t, which
+// are the last snapshots recorded at or before t of each struct type and
+// prefix, ordered by type and prefix. None are returned if no config had been
+// read at t.
+func (j *Journal) ActiveAt(t time.Time) ([]Snapshot, error) {
+	snaps, err := j.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(snaps), func(i int) bool {
+		return snaps[i].Time.After(t)
+	})
+	active := make(map[string]Snapshot)
+	for _, snap := range snaps[:i] {
+		active[snap.config()] = snap
+	}
+	out := make([]Snapshot, 0, len(active))
+	for _, snap := range active {
+		out = append(out, snap)
+	}
+	sort.Slice(out, func(a, b int) bool {
+		return out[a].config() < out[b].config()
+	})
+	return out, nil
+}
+
+// versionsHash returns a short hash of the names and versions.
+func versionsHash(versions map[string]int64) string {
+	// Hashed in order of name, so reordering fields does not change the hash
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s:%d\n", name, versions[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
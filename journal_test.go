@@ -0,0 +1,184 @@
+package ssm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type dbConfig struct {
+	Host string `ssm:"host"`
+	Port string `ssm:"port"`
+}
+
+type cacheConfig struct {
+	TTL string `ssm:"ttl"`
+}
+
+func TestWithJournal(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/host", "localhost"))
+	mock.setParam(stringParam("/port", "5432"))
+	j := NewJournal(filepath.Join(t.TempDir(), "journal"))
+	ps, err := NewParamStore(WithClient(mock), WithJournal(j))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ps.now = func() time.Time { return now }
+
+	var cfg dbConfig
+	ctx := context.Background()
+	read := func() {
+		t.Helper()
+		if err := ps.Read(ctx, &cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read()
+	now = now.Add(time.Hour)
+	read() // Unchanged, not recorded
+	now = now.Add(time.Hour)
+	mock.setParam(stringParam("/host", "db.example.com"))
+	read()
+
+	snaps, err := j.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("Got %d snapshots, want 2", len(snaps))
+	}
+	want := []Snapshot{
+		{
+			Time:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			Type:        "ssm.dbConfig",
+			Fingerprint: versionsHash(map[string]int64{"/host": 1, "/port": 1}),
+			Versions:    map[string]int64{"/host": 1, "/port": 1},
+		},
+		{
+			Time:        time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC),
+			Type:        "ssm.dbConfig",
+			Fingerprint: versionsHash(map[string]int64{"/host": 2, "/port": 1}),
+			Versions:    map[string]int64{"/host": 2, "/port": 1},
+		},
+	}
+	if diff := cmp.Diff(snaps, want); diff != "" {
+		t.Errorf("Snapshots() (-got +want)\n%s", diff)
+	}
+
+	tests := []struct {
+		at      time.Time
+		want    int
+		wantNot bool
+	}{
+		{at: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC), wantNot: true},
+		{at: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), want: 0},
+		{at: time.Date(2024, 1, 1, 13, 59, 0, 0, time.UTC), want: 0},
+		{at: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), want: 1},
+	}
+	for _, tt := range tests {
+		active, err := j.ActiveAt(tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok := len(active) > 0; ok == tt.wantNot {
+			t.Errorf("ActiveAt(%s) = %d snapshots, want %t", tt.at, len(active), !tt.wantNot)
+			continue
+		}
+		if len(active) > 0 && active[0].Fingerprint != want[tt.want].Fingerprint {
+			t.Errorf("ActiveAt(%s) = %s, want %s", tt.at, active[0].Fingerprint, want[tt.want].Fingerprint)
+		}
+	}
+}
+
+func TestWithJournal_configs(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/app/host", "localhost"))
+	mock.setParam(stringParam("/app/port", "5432"))
+	mock.setParam(stringParam("/app/ttl", "1m"))
+	j := NewJournal(filepath.Join(t.TempDir(), "journal"))
+	ps, err := NewParamStore(WithClient(mock), WithJournal(j), WithPrefix("app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ps.now = func() time.Time { return now }
+
+	// Reading the configs alternately records each once
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := ps.Read(ctx, &dbConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := ps.Read(ctx, &cacheConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Minute)
+	}
+	snaps, err := j.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("Got %d snapshots, want 2", len(snaps))
+	}
+
+	now = now.Add(time.Hour)
+	mock.setParam(stringParam("/app/host", "db.example.com"))
+	if err := ps.Read(ctx, &dbConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := j.ActiveAt(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Snapshot{
+		{
+			Time:        time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			Type:        "ssm.cacheConfig",
+			Prefix:      "/app",
+			Fingerprint: versionsHash(map[string]int64{"/app/ttl": 1}),
+			Versions:    map[string]int64{"/app/ttl": 1},
+		},
+		{
+			Time:        now,
+			Type:        "ssm.dbConfig",
+			Prefix:      "/app",
+			Fingerprint: versionsHash(map[string]int64{"/app/host": 2, "/app/port": 1}),
+			Versions:    map[string]int64{"/app/host": 2, "/app/port": 1},
+		},
+	}
+	if diff := cmp.Diff(active, want); diff != "" {
+		t.Errorf("ActiveAt() (-got +want)\n%s", diff)
+	}
+}
+
+func TestJournal_notWritten(t *testing.T) {
+	j := NewJournal(filepath.Join(t.TempDir(), "journal"))
+	snaps, err := j.Snapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 0 {
+		t.Errorf("Got %d snapshots, want 0", len(snaps))
+	}
+	if active, err := j.ActiveAt(time.Now()); len(active) != 0 || err != nil {
+		t.Errorf("ActiveAt() = %d snapshots, %v, want 0, nil", len(active), err)
+	}
+}
+
+func TestJournal_invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	if err := os.WriteFile(path, []byte("{\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJournal(path).Snapshots(); err == nil {
+		t.Fatal("Want error")
+	}
+}
@@ -0,0 +1,197 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/smithy-go"
+)
+
+// PromoteLabel moves toLabel to the version carrying fromLabel, for every
+// parameter under prefix, such as promoting the versions labeled staging to
+// prod:
+//
+//   err := ps.PromoteLabel(ctx, "/app", "staging", "prod")
+//
+// fromLabel is left in place. A label can only be on one version of a
+// parameter, so toLabel is removed from the version it was on before.
+//
+// SSM has no way to label several parameters atomically. The versions are
+// looked up before any label is changed, and a NotFoundError listing each
+// name:fromLabel is returned if a parameter under the prefix does not have
+// fromLabel, without changing any label. If labeling a parameter fails, toLabel
+// is moved back to its previous version on the parameters already promoted,
+// or removed from those that did not have it before.
+//
+// The promoted parameters are removed from the cache, including the versions
+// cached as name:toLabel by stores reading toLabel.
+func (s *ParamStore) PromoteLabel(ctx context.Context, prefix, fromLabel, toLabel string) error {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		return fmt.Errorf("prefix is required")
+	}
+	for _, label := range []string{fromLabel, toLabel} {
+		if !validLabel(label) {
+			return fmt.Errorf("invalid label %q", label)
+		}
+	}
+	if fromLabel == toLabel {
+		return fmt.Errorf("labels must differ")
+	}
+
+	params, err := s.getPath(ctx, prefix, false)
+	if err != nil {
+		return fmt.Errorf("list %s: %v", prefix, err)
+	}
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = *p.Name
+	}
+	sort.Strings(names)
+
+	type promotion struct {
+		name     string
+		version  int64 // Version with fromLabel
+		previous int64 // Version with toLabel, 0 if none
+	}
+	var (
+		promotions []promotion
+		missing    []string
+	)
+	for _, name := range names {
+		versions, err := s.labeledVersions(ctx, name)
+		if err != nil {
+			return err
+		}
+		version, ok := versions[fromLabel]
+		if !ok {
+			missing = append(missing, name+":"+fromLabel)
+			continue
+		}
+		if versions[toLabel] == version {
+			continue
+		}
+		promotions = append(promotions, promotion{name: name, version: version, previous: versions[toLabel]})
+	}
+	if len(missing) > 0 {
+		return NotFoundError{names: missing}
+	}
+
+	for i, p := range promotions {
+		err := s.labelVersion(ctx, p.name, p.version, toLabel)
+		if err == nil {
+			continue
+		}
+		var failed []string
+		for _, done := range promotions[:i] {
+			var rerr error
+			if done.previous == 0 {
+				rerr = s.unlabelVersion(ctx, done.name, done.version, toLabel)
+			} else {
+				rerr = s.labelVersion(ctx, done.name, done.previous, toLabel)
+			}
+			if rerr != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", done.name, rerr))
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("label %s: %v (roll back %s)", p.name, err, strings.Join(failed, ", "))
+		}
+		return fmt.Errorf("label %s: %v", p.name, err)
+	}
+
+	// The versions read with toLabel are cached under name:toLabel
+	promoted := make([]string, 0, 2*len(promotions))
+	for _, p := range promotions {
+		promoted = append(promoted, p.name, p.name+":"+toLabel)
+	}
+	s.forget(ctx, promoted)
+	s.log(ctx, slog.LevelInfo, "promoted label",
+		slog.String("prefix", prefix),
+		slog.String("from", fromLabel),
+		slog.String("to", toLabel),
+		slog.Int("count", len(promotions)),
+	)
+	return nil
+}
+
+// labeledVersions returns the version each label of a parameter is on.
+func (s *ParamStore) labeledVersions(ctx context.Context, name string) (map[string]int64, error) {
+	versions := make(map[string]int64)
+	input := &ssm.GetParameterHistoryInput{
+		Name: aws.String(name),
+	}
+	for {
+		var resp *ssm.GetParameterHistoryOutput
+		err := s.send(ctx, func() (err error) {
+			resp, err = s.cli.GetParameterHistory(ctx, input, s.clientOptions()...)
+			return err
+		})
+		if err != nil {
+			var aerr smithy.APIError
+			if errors.As(err, &aerr) && aerr.ErrorCode() == "ParameterNotFound" {
+				return nil, NotFoundError{names: []string{name}}
+			}
+			return nil, fmt.Errorf("get history of %s: %v", name, err)
+		}
+		for _, h := range resp.Parameters {
+			for _, label := range h.Labels {
+				versions[label] = h.Version
+			}
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+	return versions, nil
+}
+
+// labelVersion adds the label to a version of a parameter, moving it from the
+// version it was on.
+func (s *ParamStore) labelVersion(ctx context.Context, name string, version int64, label string) error {
+	input := &ssm.LabelParameterVersionInput{
+		Name:             aws.String(name),
+		ParameterVersion: aws.Int64(version),
+		Labels:           []string{label},
+	}
+	var resp *ssm.LabelParameterVersionOutput
+	err := s.send(ctx, func() (err error) {
+		resp, err = s.cli.LabelParameterVersion(ctx, input, s.clientOptions()...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.InvalidLabels) > 0 {
+		return fmt.Errorf("invalid label %s", strings.Join(resp.InvalidLabels, ", "))
+	}
+	return nil
+}
+
+// unlabelVersion removes the label from a version of a parameter.
+func (s *ParamStore) unlabelVersion(ctx context.Context, name string, version int64, label string) error {
+	input := &ssm.UnlabelParameterVersionInput{
+		Name:             aws.String(name),
+		ParameterVersion: aws.Int64(version),
+		Labels:           []string{label},
+	}
+	var resp *ssm.UnlabelParameterVersionOutput
+	err := s.send(ctx, func() (err error) {
+		resp, err = s.cli.UnlabelParameterVersion(ctx, input, s.clientOptions()...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.InvalidLabels) > 0 {
+		return fmt.Errorf("invalid label %s", strings.Join(resp.InvalidLabels, ", "))
+	}
+	return nil
+}
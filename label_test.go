@@ -0,0 +1,223 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func labelHistory(name string, labels ...[]string) []types.ParameterHistory {
+	history := make([]types.ParameterHistory, len(labels))
+	for i, l := range labels {
+		history[i] = types.ParameterHistory{
+			Name:    aws.String(name),
+			Version: int64(i + 1),
+			Labels:  l,
+		}
+	}
+	return history
+}
+
+// labelsOf returns the labels of each version of the parameter in the mock.
+func labelsOf(m *mockSSM, name string) [][]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var labels [][]string
+	for _, h := range m.history[name] {
+		labels = append(labels, h.Labels)
+	}
+	return labels
+}
+
+func TestParamStore_PromoteLabel(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/app/a", "a"),
+			stringParam("/app/b", "b"),
+			stringParam("/app/c", "c"),
+		},
+		history: map[string][]types.ParameterHistory{
+			"/app/a": labelHistory("/app/a", []string{"prod"}, []string{"staging"}),
+			"/app/b": labelHistory("/app/b", []string{"staging"}),
+			"/app/c": labelHistory("/app/c", []string{"staging", "prod"}, nil),
+		},
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ps.PromoteLabel(context.Background(), "app", "staging", "prod"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][][]string{
+		"/app/a": {nil, {"staging", "prod"}},
+		"/app/b": {{"staging", "prod"}},
+		"/app/c": {{"staging", "prod"}, nil},
+	}
+	for name, labels := range want {
+		if got := labelsOf(mock, name); !reflect.DeepEqual(got, labels) {
+			t.Errorf("%s labels = %v, want %v", name, got, labels)
+		}
+	}
+}
+
+func TestParamStore_PromoteLabel_missing(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/app/a", "a"),
+			stringParam("/app/b", "b"),
+		},
+		history: map[string][]types.ParameterHistory{
+			"/app/a": labelHistory("/app/a", []string{"staging"}),
+			"/app/b": labelHistory("/app/b", []string{"prod"}),
+		},
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ps.PromoteLabel(context.Background(), "/app", "staging", "prod")
+	var nf NotFoundError
+	if !errors.As(err, &nf) {
+		t.Fatalf("PromoteLabel() err = %v, want NotFoundError", err)
+	}
+	if want := []string{"/app/b:staging"}; !reflect.DeepEqual(nf.names, want) {
+		t.Errorf("Names = %v, want %v", nf.names, want)
+	}
+	if got := labelsOf(mock, "/app/a"); !reflect.DeepEqual(got, [][]string{{"staging"}}) {
+		t.Errorf("/app/a was labeled: %v", got)
+	}
+}
+
+func TestParamStore_PromoteLabel_cache(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			{Name: aws.String("/app/a"), Value: aws.String("old"), Type: types.ParameterTypeString, Version: 1},
+			{Name: aws.String("/app/a"), Value: aws.String("new"), Type: types.ParameterTypeString, Version: 2},
+		},
+		history: map[string][]types.ParameterHistory{
+			"/app/a": labelHistory("/app/a", []string{"prod"}, []string{"staging"}),
+		},
+	}
+	// The parameters are read with the label, and promoted by another store
+	// sharing the cache.
+	cache := NewMemoryCache()
+	ps, err := NewParamStore(WithClient(mock), WithSharedCache(cache, time.Hour), WithLabel("prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := NewParamStore(WithClient(mock), WithSharedCache(cache, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"/app/a"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "old"}})
+
+	if err := admin.PromoteLabel(context.Background(), "/app", "staging", "prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "new"}})
+}
+
+func TestParamStore_PromoteLabel_rollback(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/app/a", "a"),
+			stringParam("/app/b", "b"),
+		},
+		history: map[string][]types.ParameterHistory{
+			"/app/a": labelHistory("/app/a", []string{"prod"}, []string{"staging"}),
+			"/app/b": labelHistory("/app/b", []string{"prod"}, []string{"staging"}),
+		},
+		labelErr:  errors.New("AccessDeniedException"),
+		failLabel: "/app/b",
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ps.PromoteLabel(context.Background(), "/app", "staging", "prod"); err == nil {
+		t.Fatal("Want error")
+	}
+	for _, name := range []string{"/app/a", "/app/b"} {
+		want := [][]string{{"prod"}, {"staging"}}
+		if got := labelsOf(mock, name); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s labels = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParamStore_PromoteLabel_rollbackNew(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/app/a", "a"),
+			stringParam("/app/b", "b"),
+			stringParam("/app/c", "c"),
+		},
+		history: map[string][]types.ParameterHistory{
+			"/app/a": labelHistory("/app/a", []string{"staging"}),
+			"/app/b": labelHistory("/app/b", []string{"prod"}, []string{"staging"}),
+			"/app/c": labelHistory("/app/c", []string{"staging"}),
+		},
+		labelErr:  errors.New("AccessDeniedException"),
+		failLabel: "/app/c",
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ps.PromoteLabel(context.Background(), "/app", "staging", "prod"); err == nil {
+		t.Fatal("Want error")
+	}
+	want := map[string][][]string{
+		"/app/a": {{"staging"}},
+		"/app/b": {{"prod"}, {"staging"}},
+		"/app/c": {{"staging"}},
+	}
+	for name, labels := range want {
+		if got := labelsOf(mock, name); !reflect.DeepEqual(got, labels) {
+			t.Errorf("%s labels = %v, want %v", name, got, labels)
+		}
+	}
+}
+
+func TestParamStore_PromoteLabel_invalid(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][3]string{
+		"NoPrefix":     {"/", "staging", "prod"},
+		"InvalidFrom":  {"/app", "1st", "prod"},
+		"InvalidTo":    {"/app", "staging", "a/b"},
+		"SameLabel":    {"/app", "prod", "prod"},
+		"EmptyToLabel": {"/app", "staging", ""},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ps.PromoteLabel(context.Background(), args[0], args[1], args[2]); err == nil {
+				t.Error("Want error")
+			}
+		})
+	}
+}
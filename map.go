@@ -0,0 +1,121 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// Map fields are read from the parameters directly under a path, one entry
+// per parameter:
+//
+//   /endpoints/eu-west-1 = a.example.com,b.example.com
+//   /endpoints/us-east-1 = c.example.com
+//
+//   Endpoints map[string][]string `ssm:"endpoints"`
+//
+// In the schema, the name of a map field is the path followed by a slash.
+
+// isPath reports whether the name in a schema is the path of a map field.
+func isPath(name string) bool {
+	return strings.HasSuffix(name, "/")
+}
+
+// splitPaths separates the paths of map fields from the parameter names.
+func splitPaths(names []string) (paths, rest []string) {
+	for _, name := range names {
+		if isPath(name) {
+			paths = append(paths, name)
+		} else {
+			rest = append(rest, name)
+		}
+	}
+	return paths, rest
+}
+
+// isChild reports whether the parameter name is directly under path.
+func isChild(path, name string) bool {
+	return strings.HasPrefix(name, path) && !strings.Contains(name[len(path):], "/")
+}
+
+// children returns the parameters directly under path.
+func children(path string, params map[string]types.Parameter) map[string]types.Parameter {
+	out := make(map[string]types.Parameter)
+	for name, p := range params {
+		if isChild(path, name) {
+			out[name] = p
+		}
+	}
+	return out
+}
+
+// sortedKeys returns the names of the parameters in a and b, sorted.
+func sortedKeys(a, b map[string]types.Parameter) []string {
+	var names []string
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fetchPaths gets the parameters directly under each path.
+func (s *ParamStore) fetchPaths(ctx context.Context, paths []string) ([]types.Parameter, error) {
+	var params []types.Parameter
+	for _, path := range paths {
+		all, err := s.getPath(ctx, strings.TrimSuffix(path, "/"), s.decrypts(ctx, path))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range all {
+			if isChild(path, *p.Name) {
+				params = append(params, p)
+			}
+		}
+	}
+	return params, nil
+}
+
+// makeMap returns a map of type ty with the parameters directly under path,
+// keyed by the last element of their name. ty may be a pointer to a map.
+func (s *ParamStore) makeMap(ctx context.Context, path string, params []types.Parameter, ty reflect.Type) (reflect.Value, error) {
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if ty.Key().Kind() != reflect.String {
+		return reflect.Value{}, fmt.Errorf("unsupported map key: %s", ty.Key())
+	}
+	m := reflect.MakeMap(ty)
+	for _, p := range params {
+		if !isChild(path, *p.Name) {
+			continue
+		}
+		key := strings.TrimPrefix(*p.Name, path)
+		elem := reflect.New(ty.Elem()).Elem()
+		if err := s.setValue(ctx, p, elem); err != nil {
+			return reflect.Value{}, fmt.Errorf("set map key %s: %v", key, err)
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(ty.Key()), elem)
+	}
+	return m, nil
+}
+
+// pathType returns SecureString if any parameter directly under path is a
+// SecureString, so the map is redacted by DumpRedacted.
+func pathType(path string, params []types.Parameter) types.ParameterType {
+	for _, p := range params {
+		if isChild(path, *p.Name) && p.Type == types.ParameterTypeSecureString {
+			return types.ParameterTypeSecureString
+		}
+	}
+	return types.ParameterTypeString
+}
@@ -0,0 +1,68 @@
+package ssm
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// WithEmptyAsMissing treats parameters with an empty or blank value as if they
+// did not exist, so the default= tag option applies and Read returns a
+// NotFoundError unless the field is optional.
+//
+// By default, a parameter with an empty value is read like any other value,
+// allocating pointer fields and setting Valid of the database/sql Null types,
+// which distinguishes it from a parameter that does not exist.
+func WithEmptyAsMissing() Option {
+	return func(s *ParamStore) {
+		s.emptyAsMissing = true
+	}
+}
+
+// WithIgnoreMissing makes Read set the parameters that exist, instead of
+// returning a NotFoundError if any parameter does not exist. Fields of
+// missing parameters are left unchanged.
+//
+// The names of the missing parameters are logged at level WARN and passed to
+// onMissing, if not nil, before Read returns:
+//
+//   WithIgnoreMissing(func(names []string) {
+//       missing = names
+//   })
+func WithIgnoreMissing(onMissing func(names []string)) Option {
+	return func(s *ParamStore) {
+		s.ignoreMissing = true
+		s.onMissing = onMissing
+	}
+}
+
+// reportMissing handles parameters that were not found. A NotFoundError is
+// returned unless WithIgnoreMissing was passed.
+func (s *ParamStore) reportMissing(ctx context.Context, names []string) error {
+	if !s.ignoreMissing {
+		return NotFoundError{names: names}
+	}
+	s.log(ctx, slog.LevelWarn, "parameters not found", slog.String("names", strings.Join(names, ",")))
+	if s.onMissing != nil {
+		s.onMissing(names)
+	}
+	return nil
+}
+
+// dropEmpty removes the parameters with empty values if WithEmptyAsMissing
+// was passed.
+func (s *ParamStore) dropEmpty(params []types.Parameter) []types.Parameter {
+	if !s.emptyAsMissing {
+		return params
+	}
+	out := make([]types.Parameter, 0, len(params))
+	for _, p := range params {
+		if strings.TrimSpace(aws.ToString(p.Value)) != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
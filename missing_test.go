@@ -0,0 +1,62 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithIgnoreMissing(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	var missing []string
+	ps, err := NewParamStore(WithClient(mock), WithIgnoreMissing(func(names []string) {
+		missing = names
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := struct {
+		A string `ssm:"a"`
+		B string `ssm:"b"`
+		C string `ssm:"c,optional"`
+		D string `ssm:"d"`
+	}{
+		B: "existing",
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "A", value: "1"},
+		{path: "B", value: "existing"},
+		{path: "C", value: ""},
+		{path: "D", value: ""},
+	})
+	if diff := cmp.Diff(missing, []string{"/b", "/d"}); diff != "" {
+		t.Errorf("Missing (-got +want)\n%s", diff)
+	}
+}
+
+func TestNotFoundError_Names(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	nf, ok := err.(NotFoundError)
+	if !ok {
+		t.Fatalf("Read() err = %v, want NotFoundError", err)
+	}
+	if diff := cmp.Diff(nf.Names(), []string{"/a"}); diff != "" {
+		t.Errorf("Names() (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,88 @@
+package ssm
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// onChange is a callback registered with WithOnChange.
+type onChange struct {
+	name string
+	fn   func(old, new string)
+}
+
+// WithOnChange calls fn when the value of a parameter changes between reads,
+// for example to rebuild a connection pool only when its credentials are
+// rotated:
+//
+//   WithOnChange("db/password", func(old, new string) { pool.Reconnect(new) })
+//
+// name is either the name of the parameter, relative to the prefix unless it
+// starts with /, or the path of the field, such as "DB.Password".
+//
+// Changes are detected by every read that sets the parameter, including reads
+// by StartRefresh and Updates. fn is not called for the first read. It is
+// called after the read completes, from the goroutine that read the
+// parameters.
+func WithOnChange(name string, fn func(old, new string)) Option {
+	return func(s *ParamStore) {
+		s.onChange = append(s.onChange, onChange{name: name, fn: fn})
+	}
+}
+
+// notifyChanges records the values of the parameters read, calling the
+// callbacks registered for parameters whose value changed.
+func (s *ParamStore) notifyChanges(ctx context.Context, ty reflect.Type, schema map[string][][]int, params []types.Parameter) {
+	if len(s.onChange) == 0 {
+		return
+	}
+
+	type call struct {
+		fn       func(old, new string)
+		old, new string
+	}
+	var calls []call
+	s.mu.Lock()
+	for _, p := range params {
+		indices, ok := schema[*p.Name]
+		if !ok {
+			continue
+		}
+		value := aws.ToString(p.Value)
+		old, seen := s.lastValues[*p.Name]
+		s.lastValues[*p.Name] = value
+		if !seen || old == value {
+			continue
+		}
+		for _, c := range s.onChange {
+			if s.onChangeName(ctx, c.name) == *p.Name || hasField(ty, indices, c.name) {
+				calls = append(calls, call{fn: c.fn, old: old, new: value})
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range calls {
+		c.fn(c.old, c.new)
+	}
+}
+
+// hasField reports whether the path of any of the fields in indices is field.
+func hasField(ty reflect.Type, indices [][]int, field string) bool {
+	for _, index := range indices {
+		if strings.Join(fieldPath(ty, index), ".") == field {
+			return true
+		}
+	}
+	return false
+}
+
+// onChangeName returns the parameter name for a name passed to WithOnChange,
+// relative to the prefix of the read of ctx.
+func (s *ParamStore) onChangeName(ctx context.Context, name string) string {
+	return tagName(s.readPrefix(ctx), name)
+}
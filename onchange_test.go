@@ -0,0 +1,73 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWithOnChange(t *testing.T) {
+	type config struct {
+		Host string `ssm:"host"`
+		DB   struct {
+			User string `ssm:"user"`
+			Pass string `ssm:"pass"`
+		} `ssm:"db"`
+	}
+
+	var got []string
+	record := func(key string) func(old, new string) {
+		return func(old, new string) {
+			got = append(got, key+": "+old+" -> "+new)
+		}
+	}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/dev/host", "localhost"),
+		stringParam("/dev/db/user", "alice"),
+		secureStringParam("/dev/db/pass", "secret"),
+	}}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithOnChange("db/pass", record("name")),
+		WithOnChange("/dev/db/pass", record("absolute")),
+		WithOnChange("DB.Pass", record("field")),
+		WithOnChange("host", record("host")),
+		WithPrefix("dev"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Callbacks called on first read: %v", got)
+	}
+
+	mock.setParam(secureStringParam("/dev/db/pass", "rotated"))
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"name: secret -> rotated",
+		"absolute: secret -> rotated",
+		"field: secret -> rotated",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Callbacks (-got +want)\n%s", diff)
+	}
+
+	// Unchanged
+	got = nil
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Callbacks called without change: %v", got)
+	}
+}
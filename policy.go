@@ -0,0 +1,78 @@
+package ssm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// WithNamingPolicy checks the name of every parameter against a policy, such
+// as the naming conventions of an organization. check returns an error
+// describing why a name violates the policy, or nil if the name is valid.
+//
+// The names of a config are checked before any parameter is read, so a
+// ValidationError listing every violation is returned without making a
+// request to SSM. The same applies to the methods that write parameters,
+// such as WriteMap and Rename, which check the names before writing any of
+// them.
+//
+// Names are checked with the prefix, and without a version or label selector.
+// Names of parameters shared by ARN are not checked, as they belong to
+// another account. WithNamingPolicy may be passed more than once, in which
+// case a name must satisfy every policy.
+func WithNamingPolicy(check func(name string) error) Option {
+	return func(s *ParamStore) {
+		s.namingPolicies = append(s.namingPolicies, check)
+	}
+}
+
+// WithNamingPattern is a naming policy that requires every name to match re.
+// For example, to require lowercase names at most four levels deep:
+//
+//   ssm.WithNamingPattern(regexp.MustCompile(`^(/[a-z0-9_.-]+){1,4}$`))
+func WithNamingPattern(re *regexp.Regexp) Option {
+	return WithNamingPolicy(func(name string) error {
+		if !re.MatchString(name) {
+			return fmt.Errorf("does not match %s", re)
+		}
+		return nil
+	})
+}
+
+// checkNames checks the names against the naming policies, returning a
+// ValidationError listing the names that violate a policy.
+func (s *ParamStore) checkNames(names []string) error {
+	if len(s.namingPolicies) == 0 {
+		return nil
+	}
+	var invalid []invalidParam
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if isARN(name) {
+			continue
+		}
+		name, _ = splitSelector(name)
+		if name != "/" {
+			// The path of a map field
+			name = strings.TrimSuffix(name, "/")
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		for _, check := range s.namingPolicies {
+			if err := check(name); err != nil {
+				invalid = append(invalid, invalidParam{name: name, reason: err.Error()})
+				break
+			}
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Slice(invalid, func(i, j int) bool {
+		return invalid[i].name < invalid[j].name
+	})
+	return ValidationError{invalid: invalid}
+}
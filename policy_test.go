@@ -0,0 +1,85 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+var lowercasePolicy = WithNamingPattern(regexp.MustCompile(`^(/[a-z0-9_]+){1,3}$`))
+
+func TestNamingPolicy_Read(t *testing.T) {
+	mock := &mockSSM{}
+	ps, err := NewParamStore(WithClient(mock), WithPrefix("app"), lowercasePolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host   string `ssm:"host"`
+		APIKey string `ssm:"APIKey"`
+		DB     struct {
+			Pool struct {
+				Size int `ssm:"size"`
+			} `ssm:"pool"`
+		} `ssm:"db"`
+		Shared string            `ssm:"arn:aws:ssm:eu-west-1:123456789012:parameter/Shared"`
+		Pinned string            `ssm:"host,version=2"`
+		Hosts  map[string]string `ssm:"hosts"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	var verr ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Read() err = %v, want ValidationError", err)
+	}
+	want := []string{"/app/APIKey", "/app/db/pool/size"}
+	if got := verr.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+	if mock.calls+mock.singleCalls+mock.pathCalls > 0 {
+		t.Error("Want no requests to SSM")
+	}
+}
+
+func TestNamingPolicy_Write(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{stringParam("/app/host", "a")}}
+	ps, err := NewParamStore(WithClient(mock), lowercasePolicy,
+		WithNamingPolicy(func(name string) error {
+			if strings.Contains(name, "secret") {
+				return errors.New("use a SecureString")
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ps.WriteMap(context.Background(), "/app", map[string]string{
+		"port":   "8080",
+		"Debug":  "true",
+		"secret": "x",
+	})
+	var verr ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("WriteMap() err = %v, want ValidationError", err)
+	}
+	if want := "invalid: /app/Debug: does not match ^(/[a-z0-9_]+){1,3}$, /app/secret: use a SecureString"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	if len(mock.puts) > 0 {
+		t.Errorf("Want no parameters written, got %d", len(mock.puts))
+	}
+
+	if err := ps.Rename(context.Background(), "/app/host", "/app/Host"); !errors.As(err, &verr) {
+		t.Errorf("Rename() err = %v, want ValidationError", err)
+	}
+	if err := ps.Rename(context.Background(), "/app/host", "/app/hostname"); err != nil {
+		t.Errorf("Rename() err = %v", err)
+	}
+}
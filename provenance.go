@@ -0,0 +1,172 @@
+package ssm
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// A Source describes where the value of a field was read from.
+type Source string
+
+// Sources of values.
+const (
+	// SourceSSM is a value requested from SSM.
+	SourceSSM Source = "ssm"
+	// SourceCache is a value read from the cache set with WithCache or
+	// WithSharedCache.
+	SourceCache Source = "cache"
+	// SourceStale is a previous value served by WithStaleWhileRevalidate.
+	SourceStale Source = "stale"
+	// SourceDefault is the value of the default= tag option.
+	SourceDefault Source = "default"
+	// SourceMissing is reported for optional fields whose parameter does not
+	// exist. The field is not set.
+	SourceMissing Source = "missing"
+)
+
+// A Report describes where the values of a read came from. It is returned by
+// ReadWithReport.
+type Report struct {
+	// Fields holds a FieldReport for each field read, in the order the
+	// fields are declared.
+	Fields []FieldReport
+}
+
+// A FieldReport describes where the value of a single field came from.
+type FieldReport struct {
+	// Field is the path of the field, such as "DB.Password".
+	Field string
+	// Name is the name of the parameter the field is read from.
+	Name string
+	// Source is where the value was read from.
+	Source Source
+	// Region is the region of the SSM client, if known.
+	Region string
+	// Prefix is the prefix set with WithPrefix, if the parameter is under
+	// it. It is empty for absolute names.
+	Prefix string
+	// Version is the version of the parameter, if known.
+	Version int64
+	// Conversion describes how the value was converted to the type of the
+	// field, if WithConversionReport was passed. It is nil if the value was
+	// not converted.
+	Conversion *Conversion
+}
+
+// ReadWithReport reads configuration values into target like Read, and
+// reports where the value of each field came from. This helps debugging
+// configs read from several prefixes, regions or caches.
+func (s *ParamStore) ReadWithReport(ctx context.Context, target interface{}) (*Report, error) {
+	val, err := targetValue(target)
+	if err != nil {
+		return nil, err
+	}
+	report, _, err := s.readWithReport(ctx, val, s.prefix)
+	return report, err
+}
+
+// readWithReport reads val under prefix like ReadWithReport, returning the
+// report and the schema of val.
+func (s *ParamStore) readWithReport(ctx context.Context, val reflect.Value, prefix string) (*Report, map[string][][]int, error) {
+	rec := &sourceRecorder{
+		sources:     make(map[string]Source),
+		conversions: make(map[string]*Conversion),
+	}
+	schema, params, err := s.read(withRecorder(ctx, rec), val, prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	versions := make(map[string]int64, len(params))
+	for _, p := range params {
+		versions[*p.Name] = p.Version
+	}
+	region := s.clientRegion()
+	report := &Report{}
+	for _, f := range declFields(schema) {
+		field := strings.Join(fieldPath(val.Type(), f.index), ".")
+		fr := FieldReport{
+			Field:      field,
+			Name:       f.name,
+			Source:     rec.get(f.name),
+			Region:     region,
+			Version:    versions[f.name],
+			Conversion: rec.conversions[field],
+		}
+		if prefix != "" && strings.HasPrefix(f.name, prefix+"/") {
+			fr.Prefix = prefix
+		}
+		report.Fields = append(report.Fields, fr)
+	}
+	return report, schema, nil
+}
+
+// clientRegion returns the region set with WithRegion, or the region of the
+// client if it is an *ssm.Client.
+func (s *ParamStore) clientRegion() string {
+	if s.region != "" {
+		return s.region
+	}
+	if c, ok := s.cli.(*ssm.Client); ok {
+		return c.Options().Region
+	}
+	return ""
+}
+
+// sourceRecorder records the source of each parameter fetched by a read, and
+// the conversions of the fields set.
+type sourceRecorder struct {
+	mu          sync.Mutex
+	sources     map[string]Source
+	conversions map[string]*Conversion
+}
+
+func (r *sourceRecorder) set(source Source, names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.sources[name] = source
+	}
+}
+
+func (r *sourceRecorder) get(name string) Source {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sources[name]
+}
+
+// merge copies the sources recorded by other.
+func (r *sourceRecorder) merge(other *sourceRecorder) {
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, source := range other.sources {
+		r.sources[name] = source
+	}
+}
+
+type recorderKey struct{}
+
+func withRecorder(ctx context.Context, r *sourceRecorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// recordSource records the source of the parameters if ctx was created by
+// ReadWithReport.
+func recordSource(ctx context.Context, source Source, names ...string) {
+	if r, ok := ctx.Value(recorderKey{}).(*sourceRecorder); ok {
+		r.set(source, names...)
+	}
+}
+
+// mergeSources copies the sources recorded by r to the recorder of ctx.
+func mergeSources(ctx context.Context, r *sourceRecorder) {
+	if dst, ok := ctx.Value(recorderKey{}).(*sourceRecorder); ok {
+		dst.merge(r)
+	}
+}
@@ -0,0 +1,145 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParamStore_ReadWithReport(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/app/host", "db.example.com"),
+		stringParam("/app/port", "5432"),
+		stringParam("/shared/region", "eu-west-1"),
+	}}
+	mock.params[0].Version = 2
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPrefix("/app"),
+		WithRegion("eu-north-1"),
+		WithCache(time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		Host    string `ssm:"host"`
+		Port    string `ssm:"port"`
+		Region  string `ssm:"/shared/region"`
+		User    string `ssm:"user,default=admin"`
+		Comment string `ssm:"comment,optional"`
+	}
+
+	// Read the host into the cache.
+	var host struct {
+		Host string `ssm:"host"`
+	}
+	if err := ps.Read(context.Background(), &host); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg config
+	report, err := ps.ReadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Report{Fields: []FieldReport{
+		{Field: "Host", Name: "/app/host", Source: SourceCache, Region: "eu-north-1", Prefix: "/app", Version: 2},
+		{Field: "Port", Name: "/app/port", Source: SourceSSM, Region: "eu-north-1", Prefix: "/app"},
+		{Field: "Region", Name: "/shared/region", Source: SourceSSM, Region: "eu-north-1"},
+		{Field: "User", Name: "/app/user", Source: SourceDefault, Region: "eu-north-1", Prefix: "/app"},
+		{Field: "Comment", Name: "/app/comment", Source: SourceMissing, Region: "eu-north-1", Prefix: "/app"},
+	}}
+	if diff := cmp.Diff(report, want); diff != "" {
+		t.Errorf("Report (-got +want)\n%s", diff)
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "db.example.com"},
+		{path: "Port", value: "5432"},
+		{path: "Region", value: "eu-west-1"},
+		{path: "User", value: "admin"},
+		{path: "Comment", value: ""},
+	})
+}
+
+func TestParamStore_ReadWithReport_Stale(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Millisecond), WithStaleWhileRevalidate(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	report, err := ps.ReadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Fields[0].Source; got != SourceSSM {
+		t.Errorf("First read Source = %q, want %q", got, SourceSSM)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	report, err = ps.ReadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Fields[0].Source; got != SourceStale {
+		t.Errorf("Second read Source = %q, want %q", got, SourceStale)
+	}
+}
+
+func TestWithConversionReport(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "db.example.com"),
+		stringParam("/port", "5432"),
+		stringListParam("/weights", "0.5,1.5"),
+		stringParam("/hosts", "a;b"),
+		stringParam("/timeout", "30s"),
+	}}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithParseDuration(),
+		WithParseNumber(),
+		WithConversionReport(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host    string        `ssm:"host"`
+		Port    *int          `ssm:"port"`
+		Weights []float64     `ssm:"weights"`
+		Hosts   []string      `ssm:"hosts,sep=;"`
+		Timeout time.Duration `ssm:"timeout"`
+		Retries int           `ssm:"retries,default=3"`
+	}
+	report, err := ps.ReadWithReport(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, f := range report.Fields {
+		if f.Conversion != nil {
+			got[f.Field] = f.Conversion.String()
+		}
+	}
+	want := map[string]string{
+		"Port":    "String to *int: parse int",
+		"Weights": `StringList to []float64: split by ",", parse float`,
+		"Hosts":   `String to []string: split by ";"`,
+		"Timeout": "String to time.Duration: parse duration",
+		"Retries": "String to int: parse int",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Conversions (-got +want)\n%s", diff)
+	}
+}
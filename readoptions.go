@@ -0,0 +1,64 @@
+package ssm
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+type readOptionsKey struct{}
+
+// readOptions are the options of a single read that are set with tag options
+// on the fields of the target. They are passed with the context, as several
+// targets with different tag options may be read from the same ParamStore at
+// the same time.
+type readOptions struct {
+	// prefix is the prefix the target is read under, which differs from the
+	// prefix of the ParamStore for reads by a TenantStore.
+	prefix string
+	// noDecrypt holds the parameters read without decryption, set with the
+	// nodecrypt tag option.
+	noDecrypt map[string]bool
+	// ttls holds the cache TTLs set with the ttl= tag option.
+	ttls map[string]time.Duration
+}
+
+// withReadOptions returns a context carrying the prefix of the read and the
+// read options set on the fields in the schema.
+func (s *ParamStore) withReadOptions(ctx context.Context, t reflect.Type, prefix string, schema map[string][][]int) (context.Context, error) {
+	ttls, err := s.fieldTTLs(t, schema)
+	if err != nil {
+		return nil, err
+	}
+	o := readOptions{prefix: prefix, ttls: ttls}
+	for name, indices := range schema {
+		if s.fieldFlag(t, indices, "nodecrypt") {
+			if o.noDecrypt == nil {
+				o.noDecrypt = make(map[string]bool)
+			}
+			o.noDecrypt[name] = true
+		}
+	}
+	return context.WithValue(ctx, readOptionsKey{}, o), nil
+}
+
+// readOptionsFrom returns the read options carried by ctx.
+func readOptionsFrom(ctx context.Context) readOptions {
+	o, _ := ctx.Value(readOptionsKey{}).(readOptions)
+	return o
+}
+
+// readPrefix returns the prefix of the read of ctx, or the prefix of s if ctx
+// does not belong to a read.
+func (s *ParamStore) readPrefix(ctx context.Context) string {
+	if o, ok := ctx.Value(readOptionsKey{}).(readOptions); ok {
+		return o.prefix
+	}
+	return s.prefix
+}
+
+// detachReadOptions returns a context without deadline or values, except for
+// the read options of ctx, for fetches that outlive the read.
+func detachReadOptions(ctx context.Context) context.Context {
+	return context.WithValue(context.Background(), readOptionsKey{}, readOptionsFrom(ctx))
+}
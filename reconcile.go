@@ -0,0 +1,221 @@
+package ssm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A ReconcileOption sets an option for Reconcile.
+type ReconcileOption func(o *reconcileOptions)
+
+type reconcileOptions struct {
+	format string
+	mode   os.FileMode
+	notify func(path string)
+}
+
+// WithFileFormat sets the format of the file written by Reconcile, json or
+// env. By default, files ending in .json are written as json and other files
+// as env.
+func WithFileFormat(format string) ReconcileOption {
+	return func(o *reconcileOptions) {
+		o.format = format
+	}
+}
+
+// WithFileMode sets the permissions of the file written by Reconcile.
+// Defaults to 0600, as the file may contain decrypted secrets.
+func WithFileMode(mode os.FileMode) ReconcileOption {
+	return func(o *reconcileOptions) {
+		o.mode = mode
+	}
+}
+
+// WithNotify calls fn with the path of the file each time Reconcile writes it,
+// for example to signal a process to reload its config.
+func WithNotify(fn func(path string)) ReconcileOption {
+	return func(o *reconcileOptions) {
+		o.notify = fn
+	}
+}
+
+// Reconcile keeps the file at path in sync with the parameters under prefix,
+// so processes that cannot read from SSM themselves always have the current
+// values. The parameters are read at the interval set with WithPollInterval.
+//
+// In the json format, the file is an object with the names relative to prefix
+// as keys:
+//
+//   {
+//     "db/host": "localhost"
+//   }
+//
+// In the env format, the file has a VAR=value line per parameter. The names
+// relative to prefix are converted in the same way as by EnvProvider, so
+// db/host is written as DB_HOST. Values that are not safe to use unquoted are
+// quoted with double quotes.
+//
+// The file is only written when its content changes. It is written to a
+// temporary file in the same directory first and renamed in place, so readers
+// never see a partially written file.
+//
+// An error is returned if the file cannot be written initially. Errors after
+// that are logged and reconciling continues. Reconcile blocks until ctx is
+// done, and then returns ctx.Err().
+func (s *ParamStore) Reconcile(ctx context.Context, prefix, path string, opts ...ReconcileOption) error {
+	o := reconcileOptions{mode: 0o600}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.format == "" {
+		o.format = "env"
+		if filepath.Ext(path) == ".json" {
+			o.format = "json"
+		}
+	}
+	if o.format != "json" && o.format != "env" {
+		return fmt.Errorf("unsupported file format %q", o.format)
+	}
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		return fmt.Errorf("prefix is required")
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	current, err = s.reconcile(ctx, prefix, path, current, o)
+	if err != nil {
+		return err
+	}
+
+	t := time.NewTicker(s.pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		data, err := s.reconcile(ctx, prefix, path, current, o)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.log(ctx, slog.LevelWarn, "reconcile file",
+				slog.String("path", path),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		current = data
+	}
+}
+
+// reconcile reads the parameters under prefix and writes them to path if the
+// content differs from current. The content of the file is returned.
+func (s *ParamStore) reconcile(ctx context.Context, prefix, path string, current []byte, o reconcileOptions) ([]byte, error) {
+	params, err := s.getPath(ctx, prefix, s.decrypt)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %v", prefix, err)
+	}
+	values := make(map[string]string, len(params))
+	for _, p := range params {
+		values[strings.TrimPrefix(*p.Name, prefix+"/")] = *p.Value
+	}
+
+	var data []byte
+	if o.format == "json" {
+		data, err = renderJSON(values)
+	} else {
+		data, err = renderEnv(values)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, current) {
+		return current, nil
+	}
+	if err := writeFileAtomic(path, data, o.mode); err != nil {
+		return nil, err
+	}
+	s.log(ctx, slog.LevelInfo, "file reconciled",
+		slog.String("path", path),
+		slog.Int("params", len(values)),
+	)
+	if o.notify != nil {
+		o.notify(path)
+	}
+	return data, nil
+}
+
+func renderJSON(values map[string]string) ([]byte, error) {
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func renderEnv(values map[string]string) ([]byte, error) {
+	vars := make(map[string]string, len(values))
+	keys := make([]string, 0, len(values))
+	for name := range values {
+		key := envKey(name)
+		if other, ok := vars[key]; ok {
+			return nil, fmt.Errorf("%s and %s are both written as %s", other, name, key)
+		}
+		vars[key] = name
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		v := values[vars[key]]
+		if strings.ContainsAny(v, " \t\r\n\"'\\#$`") {
+			v = strconv.Quote(v)
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", key, v)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path
+// and renames it to path, so a concurrent reader never sees a partially
+// written file.
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("write %s: %v", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("write %s: %v", path, err)
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("write %s: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("write %s: %v", path, err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("write %s: %v", path, err)
+	}
+	return nil
+}
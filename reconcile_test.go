@@ -0,0 +1,153 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParamStore_Reconcile(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/app/db/host", "localhost"))
+	mock.setParam(stringParam("/app/greeting", "hello world"))
+	mock.setParam(stringParam("/other", "ignored"))
+	ps, err := NewParamStore(WithClient(mock), WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.env")
+	notified := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- ps.Reconcile(ctx, "app", path, WithNotify(func(path string) { notified <- path }))
+	}()
+
+	waitNotify(t, notified)
+	want := "DB_HOST=localhost\nGREETING=\"hello world\"\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("File = %q, want %q", got, want)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	mock.setParam(stringParam("/app/db/host", "db.example.com"))
+	waitNotify(t, notified)
+	want = "DB_HOST=db.example.com\nGREETING=\"hello world\"\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("File = %q, want %q", got, want)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Reconcile() error = %v, want context.Canceled", err)
+	}
+	select {
+	case <-notified:
+		t.Error("Notified without changes")
+	default:
+	}
+}
+
+func TestParamStore_Reconcile_json(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/app/db/host", "localhost"))
+	mock.setParam(stringParam("/app/port", "5432"))
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.json")
+	ctx, cancel := context.WithCancel(context.Background())
+	notify := func(string) { cancel() }
+	err = ps.Reconcile(ctx, "/app/", path, WithNotify(notify))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Reconcile() error = %v, want context.Canceled", err)
+	}
+	want := "{\n  \"db/host\": \"localhost\",\n  \"port\": \"5432\"\n}\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("File = %q, want %q", got, want)
+	}
+}
+
+func TestParamStore_Reconcile_unchanged(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/app/host", "localhost"))
+	ps, err := NewParamStore(WithClient(mock), WithPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.env")
+	if err := os.WriteFile(path, []byte("HOST=localhost\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = ps.Reconcile(ctx, "app", path, WithNotify(func(string) {
+		t.Error("Notified without changes")
+	}))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Reconcile() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestParamStore_Reconcile_errors(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/app/db-host", "a"))
+	mock.setParam(stringParam("/app/db/host", "b"))
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		prefix string
+		path   string
+		opts   []ReconcileOption
+	}{
+		{name: "NoPrefix", prefix: "/", path: filepath.Join(dir, "app.env")},
+		{name: "Format", prefix: "app", path: filepath.Join(dir, "app.env"), opts: []ReconcileOption{WithFileFormat("yaml")}},
+		{name: "Conflict", prefix: "app", path: filepath.Join(dir, "app.env")},
+		{name: "Directory", prefix: "app", path: filepath.Join(dir, "missing", "app.json")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ps.Reconcile(context.Background(), tt.prefix, tt.path, tt.opts...)
+			if err == nil {
+				t.Fatal("Want error")
+			}
+		})
+	}
+}
+
+func waitNotify(t *testing.T, ch <-chan string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for notify")
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
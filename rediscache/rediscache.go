@@ -0,0 +1,84 @@
+// Package rediscache provides an ssm.Cache backed by Redis, allowing multiple
+// processes to share cached parameters.
+package rediscache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/akupila/ssm"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultKeyPrefix is prepended to parameter names to form the Redis key.
+const DefaultKeyPrefix = "ssm:"
+
+// Cache is an ssm.Cache that stores parameters in Redis. Parameters are stored
+// as JSON and expire using the Redis TTL.
+type Cache struct {
+	client    redis.Cmdable
+	keyPrefix string
+}
+
+var _ ssm.Cache = (*Cache)(nil)
+
+// An Option sets a configuration option in the Cache.
+type Option func(c *Cache)
+
+// WithKeyPrefix sets the prefix of the keys in Redis. Defaults to
+// DefaultKeyPrefix.
+func WithKeyPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.keyPrefix = prefix
+	}
+}
+
+// New creates a new Redis cache.
+func New(client redis.Cmdable, options ...Option) *Cache {
+	c := &Cache{
+		client:    client,
+		keyPrefix: DefaultKeyPrefix,
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Get returns the cached parameter with the given name.
+func (c *Cache) Get(ctx context.Context, name string) (ssm.CachedParameter, bool, error) {
+	data, err := c.client.Get(ctx, c.keyPrefix+name).Bytes()
+	if err == redis.Nil {
+		return ssm.CachedParameter{}, false, nil
+	}
+	if err != nil {
+		return ssm.CachedParameter{}, false, fmt.Errorf("redis get: %v", err)
+	}
+	var p ssm.CachedParameter
+	if err := json.Unmarshal(data, &p); err != nil {
+		return ssm.CachedParameter{}, false, fmt.Errorf("decode %s: %v", name, err)
+	}
+	return p, true, nil
+}
+
+// Set stores the parameter for the duration of ttl.
+func (c *Cache) Set(ctx context.Context, param ssm.CachedParameter, ttl time.Duration) error {
+	data, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Errorf("encode %s: %v", param.Name, err)
+	}
+	if err := c.client.Set(ctx, c.keyPrefix+param.Name, data, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %v", err)
+	}
+	return nil
+}
+
+// Delete removes the parameter with the given name.
+func (c *Cache) Delete(ctx context.Context, name string) error {
+	if err := c.client.Del(ctx, c.keyPrefix+name).Err(); err != nil {
+		return fmt.Errorf("redis del: %v", err)
+	}
+	return nil
+}
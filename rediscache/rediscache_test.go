@@ -0,0 +1,75 @@
+package rediscache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akupila/ssm"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	c := New(client, WithKeyPrefix("test:"))
+	ctx := context.Background()
+
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() = %t, %v, want not found", ok, err)
+	}
+
+	want := ssm.CachedParameter{
+		Name:    "/foo",
+		Type:    "SecureString",
+		Value:   "bar",
+		Version: 2,
+	}
+	if err := c.Set(ctx, want, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := mr.TTL("test:/foo"); ttl != time.Minute {
+		t.Errorf("TTL = %s, want %s", ttl, time.Minute)
+	}
+
+	got, ok, err := c.Get(ctx, "/foo")
+	if err != nil || !ok {
+		t.Fatalf("Get() = %t, %v, want found", ok, err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Get() (-got +want)\n%s", diff)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() after expiry = %t, %v, want not found", ok, err)
+	}
+
+	if err := c.Set(ctx, want, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(ctx, "/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := c.Get(ctx, "/foo"); err != nil || ok {
+		t.Fatalf("Get() after Delete() = %t, %v, want not found", ok, err)
+	}
+}
+
+func TestCache_invalid(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	if err := mr.Set(DefaultKeyPrefix+"/foo", "not json"); err != nil {
+		t.Fatal(err)
+	}
+	c := New(client)
+	if _, _, err := c.Get(context.Background(), "/foo"); err == nil {
+		t.Error("Want error")
+	}
+}
@@ -0,0 +1,181 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A Refresher re-reads parameters in the background. It is created with
+// StartRefresh.
+type Refresher struct {
+	current atomic.Value
+	cancel  context.CancelFunc
+	done    chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// StartRefresh reads the parameters into target and then re-reads them every
+// interval in a background goroutine, until Stop is called or ctx is done.
+//
+// target is not modified after the initial read. Each refresh reads into a new
+// copy of the config, which replaces the value returned by Current:
+//
+//   r, err := ps.StartRefresh(ctx, &Config{}, time.Minute)
+//   if err != nil {
+//       return err
+//   }
+//   defer r.Stop()
+//   ...
+//   cfg := r.Current().(*Config)
+//
+// Only the fields whose parameters changed, as determined by their version,
+// are set in the new copy. Other fields are copied from the previous config,
+// so pointers to unchanged values remain the same. If no parameter changed,
+// Current keeps returning the previous config.
+//
+// An error is returned if the initial read fails. If a refresh fails, the
+// previous config is kept and the error is logged and returned by Err.
+func (s *ParamStore) StartRefresh(ctx context.Context, target interface{}, interval time.Duration) (*Refresher, error) {
+	val, err := targetValue(target)
+	if err != nil {
+		return nil, err
+	}
+	schema, params, err := s.read(ctx, val, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+	names := sortedNames(schema)
+
+	ctx, err = s.withReadOptions(ctx, val.Type(), s.prefix, schema)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	r := &Refresher{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	r.current.Store(target)
+
+	go func() {
+		defer close(r.done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		prev := reflect.ValueOf(target)
+		current := byName(params)
+		for {
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return
+			}
+
+			var next reflect.Value
+			params, err := s.fetchStale(ctx, names)
+			if err != nil {
+				err = fmt.Errorf("read ssm: %v", err)
+			} else {
+				next, err = s.update(ctx, prev, schema, current, params)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			r.mu.Lock()
+			r.err = err
+			r.mu.Unlock()
+			if err != nil {
+				s.log(ctx, slog.LevelWarn, "refresh parameters", slog.String("error", err.Error()))
+				continue
+			}
+			current = byName(params)
+			s.record(ctx, val.Type(), s.prefix, params)
+			if next.Pointer() != prev.Pointer() {
+				s.recordWatermarks(ctx, params)
+				prev = next
+				r.current.Store(next.Interface())
+			}
+		}
+	}()
+	return r, nil
+}
+
+// update returns a copy of the config that prev points to, with the
+// parameters that changed since current set. prev is returned if no parameter
+// changed.
+func (s *ParamStore) update(ctx context.Context, prev reflect.Value, schema map[string][][]int, current map[string]types.Parameter, params []types.Parameter) (reflect.Value, error) {
+	changedNames := diff(sortedNames(schema), current, byName(params))
+	if len(changedNames) == 0 {
+		return prev, nil
+	}
+
+	next := reflect.New(prev.Type().Elem())
+	next.Elem().Set(prev.Elem())
+	changed := make(map[string][][]int, len(changedNames))
+	for _, name := range changedNames {
+		changed[name] = schema[name]
+		for _, index := range schema[name] {
+			detach(next.Elem(), index)
+		}
+	}
+	if err := s.assign(ctx, next.Elem(), changed, params); err != nil {
+		return reflect.Value{}, err
+	}
+	return next, nil
+}
+
+// detach replaces the pointers on the path to the nested field with pointers
+// to copies, so setting the field does not modify the previous config that
+// the pointers were copied from.
+func detach(v reflect.Value, index []int) {
+	for _, i := range index {
+		v = v.Field(i)
+		if v.Kind() != reflect.Ptr {
+			continue
+		}
+		if v.IsNil() {
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(v.Elem())
+		v.Set(p)
+		v = p.Elem()
+	}
+}
+
+// Current returns a pointer to the latest config read, of the same type as the
+// target passed to StartRefresh. The returned config must not be modified.
+func (r *Refresher) Current() interface{} {
+	return r.current.Load()
+}
+
+// Err returns the error of the latest refresh, or nil if it succeeded.
+func (r *Refresher) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Stop stops refreshing and waits for the background goroutine to exit. It is
+// safe to call Stop more than once.
+func (r *Refresher) Stop() {
+	r.once.Do(r.cancel)
+	<-r.done
+}
+
+// Close calls Stop. It always returns nil, allowing a Refresher to be used as
+// an io.Closer.
+func (r *Refresher) Close() error {
+	r.Stop()
+	return nil
+}
@@ -0,0 +1,161 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_StartRefresh(t *testing.T) {
+	type config struct {
+		A string `ssm:"a"`
+	}
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initial := &config{}
+	r, err := ps.StartRefresh(context.Background(), initial, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+	if initial.A != "1" {
+		t.Errorf("A = %q, want %q", initial.A, "1")
+	}
+	if r.Current() != initial {
+		t.Errorf("Current() is not the target after the initial read")
+	}
+
+	mock.setParam(stringParam("/a", "2"))
+	waitFor(t, func() bool { return r.Current().(*config).A == "2" })
+	if initial.A != "1" {
+		t.Errorf("Target was modified after the initial read")
+	}
+
+	// Failed refresh keeps the previous config
+	mock.mu.Lock()
+	mock.err = errors.New("AccessDeniedException")
+	mock.mu.Unlock()
+	waitFor(t, func() bool { return r.Err() != nil })
+	if got := r.Current().(*config).A; got != "2" {
+		t.Errorf("A = %q after failed refresh, want %q", got, "2")
+	}
+
+	r.Stop()
+	r.Stop()
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParamStore_StartRefresh_unchanged(t *testing.T) {
+	type db struct {
+		User string `ssm:"user"`
+		Pass string `ssm:"pass"`
+	}
+	type config struct {
+		Host  *string `ssm:"host"`
+		DB    *db     `ssm:"db"`
+		Other string  `ssm:"other"`
+	}
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "h"),
+		stringParam("/db/user", "alice"),
+		stringParam("/db/pass", "1"),
+		stringParam("/other", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initial := &config{}
+	r, err := ps.StartRefresh(context.Background(), initial, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	// Nothing changed
+	waitFor(t, func() bool {
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+		return mock.calls >= 3
+	})
+	if r.Current() != initial {
+		t.Errorf("Current() replaced without changes")
+	}
+
+	mock.setParam(stringParam("/db/pass", "2"))
+	waitFor(t, func() bool { return r.Current() != initial })
+	got := r.Current().(*config)
+	if got.DB.Pass != "2" || got.DB.User != "alice" {
+		t.Errorf("DB = %+v, want updated password", got.DB)
+	}
+	if initial.DB.Pass != "1" {
+		t.Errorf("Previous config was modified")
+	}
+	if got.Host != initial.Host {
+		t.Errorf("Unchanged pointer field was replaced")
+	}
+}
+
+func TestParamStore_StartRefresh_map(t *testing.T) {
+	type config struct {
+		Endpoints map[string][]string `ssm:"endpoints"`
+	}
+	mock := &mockSSM{params: []types.Parameter{
+		stringListParam("/endpoints/eu-west-1", "a"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initial := &config{}
+	r, err := ps.StartRefresh(context.Background(), initial, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	mock.setParam(stringListParam("/endpoints/us-east-1", "b"))
+	waitFor(t, func() bool { return len(r.Current().(*config).Endpoints) == 2 })
+	if len(initial.Endpoints) != 1 {
+		t.Errorf("Previous config was modified")
+	}
+}
+
+func TestParamStore_StartRefresh_initialError(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	if _, err := ps.StartRefresh(context.Background(), &cfg, time.Minute); err == nil {
+		t.Fatal("Want error")
+	}
+}
+
+// waitFor waits until cond is true, failing the test after a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,159 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+)
+
+// An ExistsError is returned when a parameter cannot be created because it
+// already exists.
+type ExistsError struct {
+	name string
+}
+
+func (e ExistsError) Error() string {
+	return fmt.Sprintf("already exists: %s", e.name)
+}
+
+// Rename moves the parameter oldName to newName. The value, type, KMS key,
+// description and the labels of the latest version are copied to the new
+// parameter, after which the original is deleted. Names are absolute and do
+// not use the prefix.
+//
+// An ExistsError is returned if newName already exists, and a NotFoundError
+// if oldName does not exist.
+//
+// SSM has no way to rename a parameter atomically. If labeling the new
+// parameter fails, it is deleted again. If deleting the original fails, both
+// parameters exist and the error is returned.
+func (s *ParamStore) Rename(ctx context.Context, oldName, newName string) error {
+	if err := s.checkNames([]string{newName}); err != nil {
+		return err
+	}
+	latest, err := s.latestVersion(ctx, oldName)
+	if err != nil {
+		return err
+	}
+
+	put := &ssm.PutParameterInput{
+		Name:           aws.String(newName),
+		Value:          latest.Value,
+		Type:           latest.Type,
+		KeyId:          latest.KeyId,
+		Description:    latest.Description,
+		AllowedPattern: latest.AllowedPattern,
+		DataType:       latest.DataType,
+		Tier:           latest.Tier,
+		Overwrite:      aws.Bool(false),
+	}
+	if latest.Type != types.ParameterTypeSecureString {
+		put.KeyId = nil
+	}
+	var created *ssm.PutParameterOutput
+	err = s.send(ctx, func() (err error) {
+		created, err = s.cli.PutParameter(ctx, put, s.clientOptions()...)
+		return err
+	})
+	if err != nil {
+		var aerr smithy.APIError
+		if errors.As(err, &aerr) && aerr.ErrorCode() == "ParameterAlreadyExists" {
+			return ExistsError{name: newName}
+		}
+		return fmt.Errorf("create %s: %v", newName, err)
+	}
+
+	if len(latest.Labels) > 0 {
+		label := &ssm.LabelParameterVersionInput{
+			Name:             aws.String(newName),
+			ParameterVersion: aws.Int64(created.Version),
+			Labels:           latest.Labels,
+		}
+		err := s.send(ctx, func() error {
+			_, err := s.cli.LabelParameterVersion(ctx, label, s.clientOptions()...)
+			return err
+		})
+		if err != nil {
+			if derr := s.deleteOne(ctx, newName); derr != nil {
+				return fmt.Errorf("label %s: %v (delete %s: %v)", newName, err, newName, derr)
+			}
+			return fmt.Errorf("label %s: %v", newName, err)
+		}
+	}
+
+	if err := s.deleteOne(ctx, oldName); err != nil {
+		return fmt.Errorf("delete %s: %v", oldName, err)
+	}
+	s.forget(ctx, []string{oldName, newName})
+	s.log(ctx, slog.LevelInfo, "renamed parameter",
+		slog.String("old", oldName),
+		slog.String("new", newName),
+	)
+	return nil
+}
+
+// latestVersion returns the latest version of a parameter, with its value
+// decrypted.
+func (s *ParamStore) latestVersion(ctx context.Context, name string) (types.ParameterHistory, error) {
+	var (
+		latest types.ParameterHistory
+		found  bool
+	)
+	input := &ssm.GetParameterHistoryInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	}
+	for {
+		var resp *ssm.GetParameterHistoryOutput
+		err := s.send(ctx, func() (err error) {
+			resp, err = s.cli.GetParameterHistory(ctx, input, s.clientOptions()...)
+			return err
+		})
+		if err != nil {
+			var aerr smithy.APIError
+			if errors.As(err, &aerr) && aerr.ErrorCode() == "ParameterNotFound" {
+				return latest, NotFoundError{names: []string{name}}
+			}
+			return latest, fmt.Errorf("get history of %s: %v", name, err)
+		}
+		for _, h := range resp.Parameters {
+			if !found || h.Version > latest.Version {
+				latest = h
+				found = true
+			}
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+	if !found {
+		return latest, NotFoundError{names: []string{name}}
+	}
+	return latest, nil
+}
+
+// deleteOne deletes a single parameter.
+func (s *ParamStore) deleteOne(ctx context.Context, name string) error {
+	input := &ssm.DeleteParametersInput{
+		Names: []string{name},
+	}
+	var resp *ssm.DeleteParametersOutput
+	err := s.send(ctx, func() (err error) {
+		resp, err = s.cli.DeleteParameters(ctx, input, s.clientOptions()...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.InvalidParameters) > 0 {
+		return NotFoundError{names: resp.InvalidParameters}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_Rename(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			secureStringParam("/db/pass", "secret"),
+			stringParam("/other", "keep"),
+		},
+		keyIDs: map[string]string{"/db/pass": "alias/db"},
+		labels: map[string][]string{"/db/pass": {"prod"}},
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ps.Rename(context.Background(), "/db/pass", "/db/password"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []types.Parameter
+	for _, p := range mock.getParams() {
+		if *p.Name == "/db/password" {
+			got = append(got, p)
+		}
+		if *p.Name == "/db/pass" {
+			t.Error("Original parameter was not deleted")
+		}
+	}
+	if len(got) != 1 {
+		t.Fatalf("Renamed parameter not found")
+	}
+	if got[0].Type != types.ParameterTypeSecureString || *got[0].Value != "secret" {
+		t.Errorf("Renamed parameter = %s %q, want SecureString %q", got[0].Type, *got[0].Value, "secret")
+	}
+	if key := mock.keyIDs["/db/password"]; key != "alias/db" {
+		t.Errorf("KeyId = %q, want %q", key, "alias/db")
+	}
+	if labels := mock.labels["/db/password"]; !reflect.DeepEqual(labels, []string{"prod"}) {
+		t.Errorf("Labels = %v, want [prod]", labels)
+	}
+}
+
+func TestParamStore_Rename_exists(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+		stringParam("/b", "2"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ps.Rename(context.Background(), "/a", "/b")
+	if _, ok := err.(ExistsError); !ok {
+		t.Fatalf("Rename() err = %v, want ExistsError", err)
+	}
+	if got := len(mock.getParams()); got != 2 {
+		t.Errorf("Parameters = %d, want 2", got)
+	}
+}
+
+func TestParamStore_Rename_notFound(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ps.Rename(context.Background(), "/a", "/b")
+	if _, ok := err.(NotFoundError); !ok {
+		t.Fatalf("Rename() err = %v, want NotFoundError", err)
+	}
+}
+
+func TestParamStore_Rename_labelError(t *testing.T) {
+	mock := &mockSSM{
+		params:   []types.Parameter{stringParam("/a", "1")},
+		labels:   map[string][]string{"/a": {"prod"}},
+		labelErr: errors.New("AccessDeniedException"),
+	}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ps.Rename(context.Background(), "/a", "/b"); err == nil {
+		t.Fatal("Want error")
+	}
+	params := mock.getParams()
+	if len(params) != 1 || *params[0].Name != "/a" {
+		t.Errorf("Want only the original parameter to remain")
+	}
+}
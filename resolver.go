@@ -0,0 +1,281 @@
+package ssm
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A Provider provides parameter values to a Resolver.
+type Provider interface {
+	// Lookup returns the parameters with the given names. Names the provider
+	// does not have a value for are left out.
+	Lookup(ctx context.Context, names []string) ([]types.Parameter, error)
+}
+
+// ProviderFunc is a function that is used as a Provider.
+type ProviderFunc func(ctx context.Context, names []string) ([]types.Parameter, error)
+
+// Lookup calls f.
+func (f ProviderFunc) Lookup(ctx context.Context, names []string) ([]types.Parameter, error) {
+	return f(ctx, names)
+}
+
+// A MergeRule decides which value is used when several providers of a
+// Resolver have a value for the same parameter.
+type MergeRule int
+
+// Merge rules.
+const (
+	// FirstWins uses the value of the provider with the highest priority.
+	// Providers with lower priorities are only asked for the parameters not
+	// found yet.
+	FirstWins MergeRule = iota
+	// LastWins uses the value of the provider with the lowest priority.
+	LastWins
+	// ErrorOnConflict returns a ConflictError if providers have different
+	// values for the same parameter.
+	ErrorOnConflict
+)
+
+// A ConflictError is returned by a Resolver with the ErrorOnConflict rule when
+// providers have different values for a parameter.
+type ConflictError struct {
+	// Name is the name of the parameter.
+	Name string
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("conflicting values for %s", e.Name)
+}
+
+// A Resolver composes several providers, such as environment variables, a
+// local file and other ParamStores, into one source of parameters. It is used
+// by a ParamStore with WithResolver.
+type Resolver struct {
+	rule      MergeRule
+	providers []prioritized
+}
+
+type prioritized struct {
+	priority int
+	provider Provider
+}
+
+// NewResolver creates a Resolver that merges the values of its providers with
+// the rule.
+func NewResolver(rule MergeRule) *Resolver {
+	return &Resolver{rule: rule}
+}
+
+// Add adds a provider with the priority. Providers with a higher priority are
+// asked first. Providers with the same priority are asked in the order they
+// were added. Add returns r to allow chaining:
+//
+//   r := ssm.NewResolver(ssm.FirstWins).
+//       Add(20, ssm.EnvProvider("/app")).
+//       Add(10, primary).
+//       Add(0, ssm.FileProvider("defaults.env", "/app"))
+func (r *Resolver) Add(priority int, p Provider) *Resolver {
+	r.providers = append(r.providers, prioritized{priority: priority, provider: p})
+	sort.SliceStable(r.providers, func(i, j int) bool {
+		return r.providers[i].priority > r.providers[j].priority
+	})
+	return r
+}
+
+// Lookup returns the parameters with the given names, merged from the
+// providers. A Resolver is itself a Provider, so resolvers can be nested.
+func (r *Resolver) Lookup(ctx context.Context, names []string) ([]types.Parameter, error) {
+	found := make(map[string]types.Parameter, len(names))
+	rest := names
+	for _, p := range r.providers {
+		if len(rest) == 0 {
+			break
+		}
+		params, err := p.provider.Lookup(ctx, rest)
+		if err != nil {
+			return nil, err
+		}
+		for _, param := range params {
+			name := *param.Name
+			prev, ok := found[name]
+			switch {
+			case !ok:
+				found[name] = param
+			case r.rule == LastWins:
+				found[name] = param
+			case r.rule == ErrorOnConflict && aws.ToString(prev.Value) != aws.ToString(param.Value):
+				return nil, ConflictError{Name: name}
+			}
+		}
+		if r.rule == FirstWins {
+			rest = rest[:0:0]
+			for _, name := range names {
+				if _, ok := found[name]; !ok {
+					rest = append(rest, name)
+				}
+			}
+		}
+	}
+
+	params := make([]types.Parameter, 0, len(found))
+	for _, name := range names {
+		if p, ok := found[name]; ok {
+			params = append(params, p)
+		}
+	}
+	return params, nil
+}
+
+// WithResolver reads parameters from the resolver instead of directly from
+// SSM. The parameters of map fields are still read from SSM. The ParamStore
+// must not itself be a provider of the resolver.
+func WithResolver(r *Resolver) Option {
+	return func(s *ParamStore) {
+		s.resolver = r
+	}
+}
+
+// Lookup gets the parameters with the given names from SSM, allowing the
+// ParamStore to be used as a provider of a Resolver. The cache is used if
+// set. Parameters that do not exist are left out.
+func (s *ParamStore) Lookup(ctx context.Context, names []string) ([]types.Parameter, error) {
+	return s.fetchShared(ctx, names)
+}
+
+// PrefixFallback returns a provider that looks up the parameters under prefix
+// from p under fallback instead, such as shared defaults for several
+// environments:
+//
+//   ssm.PrefixFallback(ps, "/prod/app", "/shared/app")
+//
+// /prod/app/timeout is then read from /shared/app/timeout. Names not under
+// prefix are not looked up.
+func PrefixFallback(p Provider, prefix, fallback string) Provider {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	fallback = strings.TrimSuffix(fallback, "/") + "/"
+	return ProviderFunc(func(ctx context.Context, names []string) ([]types.Parameter, error) {
+		original := make(map[string]string)
+		var renamed []string
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			r := fallback + strings.TrimPrefix(name, prefix)
+			original[r] = name
+			renamed = append(renamed, r)
+		}
+		if len(renamed) == 0 {
+			return nil, nil
+		}
+		params, err := p.Lookup(ctx, renamed)
+		if err != nil {
+			return nil, err
+		}
+		var found []types.Parameter
+		for _, param := range params {
+			name, ok := original[*param.Name]
+			if !ok {
+				continue
+			}
+			param.Name = aws.String(name)
+			found = append(found, param)
+		}
+		return found, nil
+	})
+}
+
+// EnvProvider returns a provider that reads the parameters under prefix from
+// environment variables. The name relative to prefix is converted to upper
+// case, with /, - and . replaced by underscores: with the prefix /app,
+// /app/db/host is read from DB_HOST. Empty variables are ignored.
+func EnvProvider(prefix string) Provider {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	return ProviderFunc(func(ctx context.Context, names []string) ([]types.Parameter, error) {
+		var params []types.Parameter
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if v := os.Getenv(envKey(strings.TrimPrefix(name, prefix))); v != "" {
+				params = append(params, localParam(name, v))
+			}
+		}
+		return params, nil
+	})
+}
+
+var envReplacer = strings.NewReplacer("/", "_", "-", "_", ".", "_")
+
+// envKey returns the environment variable for a name relative to a prefix.
+func envKey(name string) string {
+	return strings.ToUpper(envReplacer.Replace(name))
+}
+
+// FileProvider returns a provider that reads the parameters under prefix from
+// a file of key=value lines, such as a filled in copy of the file written by
+// WriteExample. Keys are relative to prefix. Empty lines, lines starting with
+// # and keys with empty values are ignored. The file is read on every lookup;
+// a file that does not exist provides no values.
+func FileProvider(path, prefix string) Provider {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	return ProviderFunc(func(ctx context.Context, names []string) ([]types.Parameter, error) {
+		values, err := readKeyValues(path)
+		if err != nil {
+			return nil, err
+		}
+		var params []types.Parameter
+		for _, name := range names {
+			if v := values[strings.TrimPrefix(name, prefix)]; v != "" && strings.HasPrefix(name, prefix) {
+				params = append(params, localParam(name, v))
+			}
+		}
+		return params, nil
+	})
+}
+
+// readKeyValues reads the key=value lines in the file.
+func readKeyValues(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("%s:%d: missing =", path, n)
+		}
+		values[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// localParam returns a String parameter for a value not read from SSM.
+func localParam(name, value string) types.Parameter {
+	return types.Parameter{
+		Name:  aws.String(name),
+		Value: aws.String(value),
+		Type:  types.ParameterTypeString,
+	}
+}
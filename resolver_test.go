@@ -0,0 +1,137 @@
+package ssm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolver(t *testing.T) {
+	high := mapProvider{"/a": "high", "/b": "high"}
+	low := mapProvider{"/b": "low", "/c": "low"}
+
+	tests := []struct {
+		rule    MergeRule
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			rule: FirstWins,
+			want: map[string]string{"/a": "high", "/b": "high", "/c": "low"},
+		},
+		{
+			rule: LastWins,
+			want: map[string]string{"/a": "high", "/b": "low", "/c": "low"},
+		},
+		{
+			rule:    ErrorOnConflict,
+			wantErr: ConflictError{Name: "/b"},
+		},
+	}
+	for _, tt := range tests {
+		// Added in reverse to check the priorities are respected
+		r := NewResolver(tt.rule).Add(0, low).Add(10, high)
+		params, err := r.Lookup(context.Background(), []string{"/a", "/b", "/c", "/d"})
+		if err != tt.wantErr {
+			t.Errorf("Rule %d: Lookup() err = %v, want %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		got := make(map[string]string)
+		for _, p := range params {
+			got[*p.Name] = *p.Value
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("Rule %d: Lookup() (-got +want)\n%s", tt.rule, diff)
+		}
+	}
+}
+
+func TestResolver_FirstWinsSkipsFound(t *testing.T) {
+	var asked []string
+	second := ProviderFunc(func(ctx context.Context, names []string) ([]types.Parameter, error) {
+		asked = append(asked, names...)
+		return nil, nil
+	})
+	r := NewResolver(FirstWins).Add(1, mapProvider{"/a": "1"}).Add(0, second)
+	if _, err := r.Lookup(context.Background(), []string{"/a", "/b"}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(asked, []string{"/b"}); diff != "" {
+		t.Errorf("Asked (-got +want)\n%s", diff)
+	}
+}
+
+func TestWithResolver(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "defaults.env")
+	if err := os.WriteFile(file, []byte("# Defaults\nhost=file.example.com\nport=5432\nuser=\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DB_HOST", "env.example.com")
+
+	primary, err := NewParamStore(WithClient(&mockSSM{params: []types.Parameter{
+		stringParam("/app/db/user", "alice"),
+		stringParam("/shared/db/password", "secret"),
+	}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewResolver(FirstWins).
+		Add(20, EnvProvider("/app")).
+		Add(10, primary).
+		Add(5, PrefixFallback(primary, "/app", "/shared")).
+		Add(0, FileProvider(file, "/app/db"))
+	ps, err := NewParamStore(WithClient(&mockSSM{}), WithPrefix("app"), WithResolver(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		DB struct {
+			Host     string `ssm:"host"`
+			Port     string `ssm:"port"`
+			User     string `ssm:"user"`
+			Password string `ssm:"password"`
+		} `ssm:"db"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "DB.Host", value: "env.example.com"},
+		{path: "DB.Port", value: "5432"},
+		{path: "DB.User", value: "alice"},
+		{path: "DB.Password", value: "secret"},
+	})
+}
+
+func TestFileProvider_NotExist(t *testing.T) {
+	p := FileProvider(filepath.Join(t.TempDir(), "missing.env"), "/")
+	params, err := p.Lookup(context.Background(), []string{"/a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 0 {
+		t.Errorf("Lookup() = %v, want none", params)
+	}
+}
+
+// mapProvider provides the values in the map.
+type mapProvider map[string]string
+
+func (m mapProvider) Lookup(ctx context.Context, names []string) ([]types.Parameter, error) {
+	var params []types.Parameter
+	for _, name := range names {
+		if v, ok := m[name]; ok {
+			params = append(params, stringParam(name, v))
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,111 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"golang.org/x/time/rate"
+)
+
+// WithRetry retries requests that fail with a transient error, such as
+// throttling, up to maxAttempts times in total. The delay between attempts
+// starts at baseDelay and doubles after each attempt, with random jitter.
+//
+// Retries stop if the context is canceled, or if the delay would exceed the
+// deadline of the context.
+//
+// The retryer of the SDK client is disabled for the requests, so each attempt
+// is a single request. Clients passed to WithClient that are not an SDK
+// client, such as one wrapped with ssmv1.Wrap, may still retry each attempt
+// themselves.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(s *ParamStore) {
+		s.maxAttempts = maxAttempts
+		s.baseDelay = baseDelay
+	}
+}
+
+// WithRateLimit limits the rate of requests made to SSM to rps requests per
+// second, allowing bursts of up to burst requests. Requests wait until they are
+// allowed by the limit, or the context is done.
+//
+// This is useful to avoid throttling when many processes read parameters at
+// the same time.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(s *ParamStore) {
+		s.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// send calls fn, which makes a request to SSM, until it succeeds, returns an
+// error that is not transient, or the maximum number of attempts is reached.
+// Each attempt waits for the rate limit.
+func (s *ParamStore) send(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		if s.limiter != nil {
+			if err := s.limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		err := s.schedule(ctx, fn)
+		if s.tuner != nil && isThrottle(err) {
+			s.tuner.throttle()
+		}
+		if err == nil || attempt >= s.maxAttempts || !isTransient(err) {
+			return err
+		}
+
+		// Full jitter: sleep a random duration up to the exponential delay.
+		max := s.baseDelay << uint(attempt-1)
+		if max <= 0 {
+			return err
+		}
+		delay := time.Duration(rand.Int63n(int64(max)))
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		}
+	}
+}
+
+// schedule calls fn when it is allowed by the scheduler, if set.
+func (s *ParamStore) schedule(ctx context.Context, fn func() error) error {
+	if s.scheduler == nil {
+		return fn()
+	}
+	release, err := s.scheduler.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+// transientCodes are error codes for errors that may succeed if retried.
+var transientCodes = map[string]bool{
+	"ThrottlingException":     true,
+	"TooManyRequests":         true,
+	"RequestLimitExceeded":    true,
+	"InternalServerError":     true,
+	"ServiceUnavailable":      true,
+	"RequestTimeout":          true,
+	"RequestTimeoutException": true,
+}
+
+// isTransient reports whether err is a transient error.
+func isTransient(err error) bool {
+	var aerr smithy.APIError
+	if errors.As(err, &aerr) {
+		return transientCodes[aerr.ErrorCode()]
+	}
+	return false
+}
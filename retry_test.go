@@ -0,0 +1,196 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestParamStore_Read_retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []Option
+		throttle  int
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "NoRetry",
+			throttle:  1,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "Success",
+			options:   []Option{WithRetry(3, time.Millisecond)},
+			throttle:  2,
+			wantCalls: 3,
+		},
+		{
+			name:      "MaxAttempts",
+			options:   []Option{WithRetry(3, time.Millisecond)},
+			throttle:  3,
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "NotTransient",
+			options:   []Option{WithRetry(3, time.Millisecond)},
+			err:       &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "Access denied"},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "OtherError",
+			options:   []Option{WithRetry(3, time.Millisecond)},
+			err:       fmt.Errorf("error"),
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSSM{
+				params:   []types.Parameter{stringParam("/foo", "bar")},
+				throttle: tt.throttle,
+				err:      tt.err,
+			}
+			ps, err := NewParamStore(append(tt.options, WithClient(mock))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var cfg struct {
+				Foo string `ssm:"foo"`
+			}
+			err = ps.Read(context.Background(), &cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() err = %v, want err = %t", err, tt.wantErr)
+			}
+			if mock.calls != tt.wantCalls {
+				t.Errorf("GetParameters called %d times, want %d", mock.calls, tt.wantCalls)
+			}
+			if !tt.wantErr {
+				check(t, cfg, []value{{path: "Foo", value: "bar"}})
+			}
+		})
+	}
+}
+
+func TestParamStore_Read_retryDeadline(t *testing.T) {
+	mock := &mockSSM{
+		params:   []types.Parameter{stringParam("/foo", "bar")},
+		throttle: 10,
+	}
+	ps, err := NewParamStore(WithClient(mock), WithRetry(10, time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var cfg struct {
+		Foo string `ssm:"foo"`
+	}
+	start := time.Now()
+	if err := ps.Read(ctx, &cfg); err == nil {
+		t.Fatal("Want error")
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("Read took %s, want to stop retrying at deadline", d)
+	}
+}
+
+func TestParamStore_Read_rateLimit(t *testing.T) {
+	ty, params, want := largeConfig(55)
+	mock := &mockSSM{params: params}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithMaxConcurrency(6),
+		WithRateLimit(100, 2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	val := reflect.New(ty)
+	start := time.Now()
+	if err := ps.Read(context.Background(), val.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	check(t, val.Elem().Interface(), want)
+	// 2 requests are allowed immediately, the remaining 4 wait 10ms each.
+	if d := time.Since(start); d < 35*time.Millisecond {
+		t.Errorf("Read took %s, want at least 40ms", d)
+	}
+}
+
+func TestParamStore_Read_rateLimitCanceled(t *testing.T) {
+	ty, params, _ := largeConfig(20)
+	mock := &mockSSM{params: params}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithRateLimit(0.1, 1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := ps.Read(ctx, reflect.New(ty).Interface()); err == nil {
+		t.Fatal("Want error")
+	}
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+}
+
+func TestWithRetry_sdkRetryer(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"__type":"ThrottlingException","message":"Rate exceeded"}`)
+	}))
+	defer srv.Close()
+
+	cfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}
+	ps, err := NewParamStore(
+		WithAWSConfig(cfg),
+		WithEndpoint(srv.URL),
+		WithHTTPClient(srv.Client()),
+		WithRetry(2, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Foo string `ssm:"foo"`
+	}
+	if err := ps.Read(context.Background(), &got); err == nil {
+		t.Fatal("Want error")
+	}
+	// Without disabling the retryer of the SDK, each attempt is retried
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Endpoint called %d times, want 2", n)
+	}
+}
@@ -0,0 +1,67 @@
+package ssm
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// A Scheduler coordinates the requests made to SSM by several ParamStores in
+// the same process, such as stores for different prefixes or structs, under a
+// single rate limit. The throughput of SSM is limited per account and region,
+// so limiting each ParamStore with WithRateLimit does not prevent the stores
+// from exceeding it together.
+type Scheduler struct {
+	limiter *rate.Limiter
+	slots   chan struct{}
+}
+
+// NewScheduler creates a Scheduler that allows rps requests per second, with
+// bursts of up to burst requests. If maxInFlight is greater than 0, at most
+// maxInFlight requests are made at the same time.
+func NewScheduler(rps float64, burst, maxInFlight int) *Scheduler {
+	sch := &Scheduler{limiter: rate.NewLimiter(rate.Limit(rps), burst)}
+	if maxInFlight > 0 {
+		sch.slots = make(chan struct{}, maxInFlight)
+	}
+	return sch
+}
+
+// WithScheduler makes the requests of the ParamStore through the scheduler,
+// which may be shared with other ParamStores:
+//
+//   sch := ssm.NewScheduler(40, 10, 5)
+//   app, err := ssm.NewParamStore(ssm.WithPrefix("app"), ssm.WithScheduler(sch))
+//   ...
+//   shared, err := ssm.NewParamStore(ssm.WithPrefix("shared"), ssm.WithScheduler(sch))
+//
+// Requests wait until they are allowed by the scheduler, or the context is
+// done. Retries wait for the scheduler again. WithRateLimit may still be used
+// to limit a single ParamStore further.
+func WithScheduler(sch *Scheduler) Option {
+	return func(s *ParamStore) {
+		s.scheduler = sch
+	}
+}
+
+// acquire waits until a request is allowed. release must be called when the
+// request is done.
+func (sch *Scheduler) acquire(ctx context.Context) (release func(), err error) {
+	if sch.slots != nil {
+		select {
+		case sch.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release = func() {
+		if sch.slots != nil {
+			<-sch.slots
+		}
+	}
+	if err := sch.limiter.Wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
@@ -0,0 +1,87 @@
+package ssm
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithScheduler(t *testing.T) {
+	ty, params, want := largeConfig(55)
+	mock := &mockSSM{params: params, delay: 5 * time.Millisecond}
+	sch := NewScheduler(1000, 10, 2)
+	var stores []*ParamStore
+	for i := 0; i < 3; i++ {
+		ps, err := NewParamStore(WithClient(mock), WithMaxConcurrency(6), WithScheduler(sch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stores = append(stores, ps)
+	}
+
+	var wg sync.WaitGroup
+	for _, ps := range stores {
+		wg.Add(1)
+		go func(ps *ParamStore) {
+			defer wg.Done()
+			val := reflect.New(ty)
+			if err := ps.Read(context.Background(), val.Interface()); err != nil {
+				t.Error(err)
+				return
+			}
+			check(t, val.Elem().Interface(), want)
+		}(ps)
+	}
+	wg.Wait()
+	if mock.calls != 18 {
+		t.Errorf("GetParameters called %d times, want 18", mock.calls)
+	}
+	if mock.maxInFlight != 2 {
+		t.Errorf("Max concurrent calls = %d, want 2", mock.maxInFlight)
+	}
+}
+
+func TestWithScheduler_rateLimit(t *testing.T) {
+	ty, params, _ := largeConfig(20)
+	mock := &mockSSM{params: params}
+	sch := NewScheduler(100, 1, 0)
+	a, err := NewParamStore(WithClient(mock), WithScheduler(sch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewParamStore(WithClient(mock), WithScheduler(sch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for _, ps := range []*ParamStore{a, b} {
+		if err := ps.Read(context.Background(), reflect.New(ty).Interface()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 1 request is allowed immediately, the remaining 3 wait 10ms each.
+	if d := time.Since(start); d < 25*time.Millisecond {
+		t.Errorf("Read took %s, want at least 30ms", d)
+	}
+}
+
+func TestWithScheduler_canceled(t *testing.T) {
+	ty, params, _ := largeConfig(20)
+	mock := &mockSSM{params: params}
+	ps, err := NewParamStore(WithClient(mock), WithScheduler(NewScheduler(0.1, 1, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := ps.Read(ctx, reflect.New(ty).Interface()); err == nil {
+		t.Fatal("Want error")
+	}
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+}
@@ -0,0 +1,43 @@
+package ssm
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// Parameters shared from another account with AWS Resource Access Manager are
+// read by their full ARN:
+//
+//   DB string `ssm:"arn:aws:ssm:eu-west-1:123456789012:parameter/shared/db"`
+//
+// The ARN is used as the name of the parameter, so it is not prefixed. The
+// parameter must be in the region of the SSM client.
+
+// isARN reports whether the name in a schema is the ARN of a shared parameter.
+func isARN(name string) bool {
+	return strings.HasPrefix(name, "arn:")
+}
+
+// matchARNs sets the name of parameters that were requested by ARN to the
+// ARN, so they can be matched with the schema. SSM returns shared parameters
+// with the name they have in the account that owns them.
+func matchARNs(names []string, params []types.Parameter) []types.Parameter {
+	arns := make(map[string]bool)
+	for _, name := range names {
+		if isARN(name) {
+			arns[name] = true
+		}
+	}
+	if len(arns) == 0 {
+		return params
+	}
+	for i, p := range params {
+		arn := aws.ToString(p.ARN) + aws.ToString(p.Selector)
+		if arns[arn] && aws.ToString(p.Name) != arn {
+			params[i].Name = aws.String(arn)
+		}
+	}
+	return params
+}
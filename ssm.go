@@ -2,24 +2,48 @@ package ssm
 
 import (
 	"context"
+	"database/sql"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
 )
 
-// Client is the SSM client.
+// Client is the SSM client. It is implemented by *ssm.Client from
+// github.com/aws/aws-sdk-go-v2/service/ssm.
 type Client interface {
-	GetParametersRequest(input *ssm.GetParametersInput) ssm.GetParametersRequest
+	GetParameter(ctx context.Context, input *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+	GetParameters(ctx context.Context, input *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
+	GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error)
+	DescribeParameters(ctx context.Context, input *ssm.DescribeParametersInput, optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
+	PutParameter(ctx context.Context, input *ssm.PutParameterInput, optFns ...func(*ssm.Options)) (*ssm.PutParameterOutput, error)
+	DeleteParameters(ctx context.Context, input *ssm.DeleteParametersInput, optFns ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error)
+	GetParameterHistory(ctx context.Context, input *ssm.GetParameterHistoryInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error)
+	LabelParameterVersion(ctx context.Context, input *ssm.LabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.LabelParameterVersionOutput, error)
+	UnlabelParameterVersion(ctx context.Context, input *ssm.UnlabelParameterVersionInput, optFns ...func(*ssm.Options)) (*ssm.UnlabelParameterVersionOutput, error)
+	AddTagsToResource(ctx context.Context, input *ssm.AddTagsToResourceInput, optFns ...func(*ssm.Options)) (*ssm.AddTagsToResourceOutput, error)
 }
 
 // A NotFoundError is returned when one or more of the requested parameters was
-// not found.
+// not found. Parameters read into fields with the optional or default= tag
+// option are not required to exist.
 type NotFoundError struct {
 	names []string
 }
@@ -28,14 +52,102 @@ func (e NotFoundError) Error() string {
 	return fmt.Sprintf("not found: %v", strings.Join(e.names, ", "))
 }
 
+// Names returns the names of the parameters that were not found.
+func (e NotFoundError) Names() []string {
+	return e.names
+}
+
 // ParamStore reads configuration values from SSM Parameter Store.
+//
+// A ParamStore is safe for concurrent use by multiple goroutines, including
+// concurrent reads of the same target type, as long as each read has its own
+// target. Functions passed to options, such as converters and callbacks, may
+// be called concurrently and must be safe for concurrent use. Options are only
+// applied by NewParamStore; the configuration does not change afterwards.
 type ParamStore struct {
-	prefix string
-	tag    string
+	prefix     string
+	tag        string
+	splitWords bool
+	autoKeys   bool
+	keyFunc    func(fieldPath []string) string
+	protoNames bool
+	fieldMask  []string
+	env        string
+
+	conversionReport bool
+	label            string
+
+	descriptions   bool
+	emptyAsMissing bool
+	ignoreMissing  bool
+	onMissing      func(names []string)
+	pathFetch      bool
+	decrypt        bool
+	// decryptFallback and decryptTimeout are set with
+	// WithDecryptionFallback.
+	decryptFallback bool
+	decryptTimeout  time.Duration
+	maxConcurrency  int
+	readBudget      time.Duration
+
+	converters []func(ctx context.Context, param types.Parameter, value reflect.Value) (bool, error)
+	// types are struct types handled by a converter, which are read from a
+	// single parameter instead of as nested values.
+	types map[reflect.Type]bool
+
+	cli          Client
+	resolver     *Resolver
+	awsConfig    *aws.Config
+	endpoint     string
+	region       string
+	httpClient   aws.HTTPClient
+	apiOptions   []func(*middleware.Stack) error
+	appName      string
+	logger       *slog.Logger
+	pollInterval time.Duration
 
-	converters []func(param ssm.Parameter, value reflect.Value) (bool, error)
+	cache       Cache
+	cacheTTL    time.Duration
+	notFoundTTL time.Duration
 
-	cli Client
+	maxAttempts int
+	baseDelay   time.Duration
+	limiter     *rate.Limiter
+	scheduler   *Scheduler
+	journal     *Journal
+	tuner       *tuner
+	watermark   func(w Watermark)
+
+	namingPolicies []func(name string) error
+
+	mu sync.Mutex
+	// paramTypes holds the types of parameters that have been read, used to
+	// redact secure values.
+	paramTypes map[string]types.ParameterType
+	// notFound holds the expiry of parameters that were not found.
+	notFound map[string]time.Time
+	// selectors holds the version and label selectors each parameter has
+	// been read with, so every cached version is removed by forget.
+	selectors map[string]map[string]bool
+	// flights holds the fetches in progress, by the names fetched.
+	flights map[string]*flight
+	// lastValues holds the latest value read of each parameter, to detect
+	// changes for onChange.
+	lastValues map[string]string
+	// deprecated holds the deprecated parameters that a warning has been
+	// logged for.
+	deprecated map[string]bool
+	onChange   []onChange
+
+	staleWhileRevalidate bool
+	onRevalidateError    func(err error)
+	// stale holds the last values fetched, and revalidating the sets of
+	// names being refreshed, for WithStaleWhileRevalidate.
+	stale        map[string]staleEntry
+	revalidating map[string]bool
+
+	// now is replaced in tests.
+	now func() time.Time
 }
 
 // An Option sets a configuration option in the ParamStore.
@@ -47,20 +159,46 @@ type Option func(s *ParamStore)
 func NewParamStore(options ...Option) (*ParamStore, error) {
 	s := &ParamStore{
 		// Defaults
-		tag: "ssm",
+		tag:          "ssm",
+		pollInterval: DefaultPollInterval,
+		types:        make(map[reflect.Type]bool),
+		paramTypes:   make(map[string]types.ParameterType),
+		decrypt:      true,
+		notFound:     make(map[string]time.Time),
+		selectors:    make(map[string]map[string]bool),
+		flights:      make(map[string]*flight),
+		lastValues:   make(map[string]string),
+		deprecated:   make(map[string]bool),
+		stale:        make(map[string]staleEntry),
+		revalidating: make(map[string]bool),
+		now:          time.Now,
 	}
 
 	for _, opt := range options {
 		opt(s)
 	}
 
-	// If cli was not set, load external config.
+	// If cli was not set, create it from the aws config, loading the default
+	// config if no config was set.
 	if s.cli == nil {
-		cfg, err := external.LoadDefaultAWSConfig()
-		if err != nil {
-			return nil, fmt.Errorf("load external aws config: %v", err)
+		if s.awsConfig == nil {
+			cfg, err := config.LoadDefaultConfig(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("load default aws config: %v", err)
+			}
+			s.awsConfig = &cfg
 		}
-		client := ssm.New(cfg)
+		client := ssm.NewFromConfig(*s.awsConfig, func(o *ssm.Options) {
+			if s.region != "" {
+				o.Region = s.region
+			}
+			if s.endpoint != "" {
+				o.BaseEndpoint = aws.String(s.endpoint)
+			}
+			if s.httpClient != nil {
+				o.HTTPClient = s.httpClient
+			}
+		})
 		WithClient(client)(s)
 	}
 
@@ -91,10 +229,164 @@ func WithTag(tag string) Option {
 	}
 }
 
+// WithSplitWords derives the parameter name from the field name when the
+// struct tag does not specify a name. CamelCase names are converted to
+// snake_case:
+//
+//   type Config struct {
+//       ClientID string `ssm:""`          // /client_id
+//       APIKey   string `ssm:",optional"` // /api_key
+//   }
+func WithSplitWords() Option {
+	return func(s *ParamStore) {
+		s.splitWords = true
+	}
+}
+
+// WithAutoKeys reads exported fields without a struct tag, deriving the
+// parameter name from the field name like WithSplitWords:
+//
+//   type Config struct {
+//       ClientID string // /client_id
+//       APIKey   string `ssm:",optional"` // /api_key
+//       internal string // Not read
+//   }
+//
+// Fields of nested structs are read in the same way. Every exported field must
+// be of a supported type; other fields can be skipped with `ssm:"-"`.
+func WithAutoKeys() Option {
+	return func(s *ParamStore) {
+		s.splitWords = true
+		s.autoKeys = true
+	}
+}
+
+// WithKeyFunc derives the parameter name of fields without a name in the
+// struct tag with fn, instead of converting the field name to snake_case like
+// WithSplitWords. fn is passed the names of the field and its parent structs
+// that have no name, and returns the name relative to the closest parent with
+// a name, or the prefix. The name may contain / to create nested parameters:
+//
+//   ssm.WithKeyFunc(func(fieldPath []string) string {
+//       return strings.ToLower(strings.Join(fieldPath, "-"))
+//   })
+//
+//   type Config struct {
+//       DB struct {
+//           HostName string `ssm:""` // /db-hostname
+//       } `ssm:""`
+//       Cache struct {
+//           HostName string `ssm:""` // /cache/hostname
+//       } `ssm:"cache"`
+//   }
+func WithKeyFunc(fn func(fieldPath []string) string) Option {
+	return func(s *ParamStore) {
+		s.splitWords = true
+		s.keyFunc = fn
+	}
+}
+
+// WithProtoNames reads fields without a struct tag using the field name in the
+// protobuf struct tag. This allows reading into structs generated by
+// protoc-gen-go:
+//
+//   type Config struct {
+//       state protoimpl.MessageState
+//       ...
+//       DisplayName string `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3"`
+//   }
+//
+// DisplayName is read from /display_name. Nested messages are read as nested
+// values. Oneof and map fields are not supported.
+func WithProtoNames() Option {
+	return func(s *ParamStore) {
+		s.protoNames = true
+	}
+}
+
+// WithFieldMask only reads the parameters included in the field mask. Paths
+// are relative to the prefix and separated by dots, following the format of
+// google.protobuf.FieldMask:
+//
+//   WithFieldMask("host", "db.user") // reads /host and /db/user
+//
+// A path that refers to a nested struct includes all of its fields. Parameters
+// with absolute names outside the prefix are matched by their full name.
+func WithFieldMask(paths ...string) Option {
+	return func(s *ParamStore) {
+		s.fieldMask = paths
+	}
+}
+
+// WithEnvironment sets the environment the config is read in. Fields with the
+// envs= tag option are only read in the listed environments, allowing one
+// struct to be used in environments with different parameters:
+//
+//   type Config struct {
+//       Host     string `ssm:"host"`
+//       Replicas string `ssm:"replicas,envs=prod|staging"`
+//   }
+//
+// The environments are separated by |, or by commas if the list is quoted, as
+// in envs='prod,staging'.
+//
+// Fields with the envs= option are not read if no environment is set.
+func WithEnvironment(env string) Option {
+	return func(s *ParamStore) {
+		s.env = env
+	}
+}
+
+// inEnvironment reports whether the environment is in the list of
+// environments.
+func (s *ParamStore) inEnvironment(envs []string) bool {
+	for _, env := range envs {
+		if s.env != "" && env == s.env {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDescriptions includes the description of a parameter in the error if
+// its value cannot be converted. This allows describing the expected value in
+// SSM:
+//
+//   /timeout: time: missing unit in duration "30" (expected duration, e.g. 30s)
+//
+// The description is only fetched when an error occurs.
+func WithDescriptions() Option {
+	return func(s *ParamStore) {
+		s.descriptions = true
+	}
+}
+
+// WithPathFetch reads all parameters under the prefix with
+// GetParametersByPath, instead of requesting them by name with GetParameters.
+// This requires fewer calls when the parameters in the struct are most of the
+// parameters under the prefix.
+//
+// Parameters not under the prefix are still requested by name. WithPathFetch
+// has no effect if no prefix was set.
+func WithPathFetch() Option {
+	return func(s *ParamStore) {
+		s.pathFetch = true
+	}
+}
+
+// WithMaxConcurrency sets the maximum number of GetParameters requests made
+// at the same time. Structs with more than 10 parameters are read in multiple
+// requests, which by default are made one at a time.
+func WithMaxConcurrency(n int) Option {
+	return func(s *ParamStore) {
+		s.maxConcurrency = n
+	}
+}
+
 // WithParseDuration parses a duration string to a time.Duration.
 func WithParseDuration() Option {
 	return func(s *ParamStore) {
-		fn := func(param ssm.Parameter, value reflect.Value) (bool, error) {
+		fn := func(_ context.Context, param types.Parameter, value reflect.Value) (bool, error) {
 			if value.Type() != reflect.TypeOf((time.Duration)(0)) {
 				return false, nil
 			}
@@ -112,7 +404,7 @@ func WithParseDuration() Option {
 // WithParseTime parses a time string with the given layout to a time.Time.
 func WithParseTime(layout string) Option {
 	return func(s *ParamStore) {
-		fn := func(param ssm.Parameter, value reflect.Value) (bool, error) {
+		fn := func(_ context.Context, param types.Parameter, value reflect.Value) (bool, error) {
 			if value.Type() != reflect.TypeOf(time.Time{}) {
 				return false, nil
 			}
@@ -127,20 +419,29 @@ func WithParseTime(layout string) Option {
 	}
 }
 
-// WithParseNumber enables parsing strings and lists of strings to ints and
-// floats.
+// WithParseNumber enables parsing strings and lists of strings to ints,
+// unsigned ints and floats. Values that do not fit in an int or unsigned int
+// of the size of the field are an error.
 func WithParseNumber() Option {
 	return func(s *ParamStore) {
-		fn := func(param ssm.Parameter, value reflect.Value) (bool, error) {
+		fn := func(_ context.Context, param types.Parameter, value reflect.Value) (bool, error) {
 			switch value.Kind() {
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				num, err := strconv.ParseInt(*param.Value, 10, 64)
+				num, err := strconv.ParseInt(*param.Value, 10, value.Type().Bits())
 				if err != nil {
 					nerr := err.(*strconv.NumError)
-					return false, fmt.Errorf("parse %q as int: %v", nerr.Num, nerr.Err)
+					return false, fmt.Errorf("parse %q as %s: %v", nerr.Num, value.Type(), nerr.Err)
 				}
 				value.SetInt(num)
 				return true, nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				num, err := strconv.ParseUint(*param.Value, 10, value.Type().Bits())
+				if err != nil {
+					nerr := err.(*strconv.NumError)
+					return false, fmt.Errorf("parse %q as %s: %v", nerr.Num, value.Type(), nerr.Err)
+				}
+				value.SetUint(num)
+				return true, nil
 			case reflect.Float32, reflect.Float64:
 				num, err := strconv.ParseFloat(*param.Value, 64)
 				if err != nil {
@@ -156,6 +457,169 @@ func WithParseNumber() Option {
 	}
 }
 
+// WithParseBool enables parsing strings and lists of strings to bools. The
+// values true, false, yes, no, 1 and 0 are accepted, in any case.
+func WithParseBool() Option {
+	return func(s *ParamStore) {
+		fn := func(_ context.Context, param types.Parameter, value reflect.Value) (bool, error) {
+			if value.Kind() != reflect.Bool {
+				return false, nil
+			}
+			b, err := parseBool(*param.Value)
+			if err != nil {
+				return false, err
+			}
+			value.SetBool(b)
+			return true, nil
+		}
+		s.converters = append(s.converters, fn)
+	}
+}
+
+// parseBool parses the bool values accepted by WithParseBool.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("parse %q as bool: invalid syntax", value)
+}
+
+// WithParseLocation loads IANA time zone names, such as Europe/Helsinki, into
+// *time.Location fields with time.LoadLocation. The field is set to the
+// location returned, so UTC is read as time.UTC. Local and empty names are an
+// error, as they depend on the host.
+func WithParseLocation() Option {
+	return func(s *ParamStore) {
+		typ := reflect.TypeOf((*time.Location)(nil))
+		fn := func(_ context.Context, param types.Parameter, value reflect.Value) (bool, error) {
+			if value.Type() != typ {
+				return false, nil
+			}
+			if *param.Value == "" || *param.Value == "Local" {
+				return false, fmt.Errorf("parse %q as location: not a time zone name", *param.Value)
+			}
+			loc, err := time.LoadLocation(*param.Value)
+			if err != nil {
+				return false, fmt.Errorf("parse %q as location: %v", *param.Value, err)
+			}
+			value.Set(reflect.ValueOf(loc))
+			return true, nil
+		}
+		s.converters = append(s.converters, fn)
+		s.types[typ] = true
+	}
+}
+
+// A ConverterFunc converts the value of a parameter to a value of the type it
+// was registered for with WithConverterFunc. ctx is the context passed to
+// Read, so converters that do I/O, such as resolving a reference to another
+// system, can respect its cancellation and deadline.
+type ConverterFunc func(ctx context.Context, value string) (interface{}, error)
+
+// WithConverter adds a converter for fields of type typ. The function is
+// called with the value of the parameter and must return a value assignable to
+// typ. Converters that need the context of the read are added with
+// WithConverterFunc.
+//
+// Struct types with a converter are read from a single parameter instead of as
+// nested values.
+func WithConverter(typ reflect.Type, fn func(value string) (interface{}, error)) Option {
+	return WithConverterFunc(typ, func(_ context.Context, value string) (interface{}, error) {
+		return fn(value)
+	})
+}
+
+// WithConverterFunc adds a converter for fields of type typ, like
+// WithConverter, that is called with the context of the read. The read fails
+// with the error returned by fn, such as ctx.Err() if the read was canceled.
+func WithConverterFunc(typ reflect.Type, fn ConverterFunc) Option {
+	return func(s *ParamStore) {
+		conv := func(ctx context.Context, param types.Parameter, value reflect.Value) (bool, error) {
+			if value.Type() != typ {
+				return false, nil
+			}
+			v, err := fn(ctx, *param.Value)
+			if err != nil {
+				return false, err
+			}
+			rv := reflect.ValueOf(v)
+			if !rv.IsValid() || !rv.Type().AssignableTo(typ) {
+				return false, fmt.Errorf("converter returned %T, want %s", v, typ)
+			}
+			value.Set(rv)
+			return true, nil
+		}
+		s.converters = append(s.converters, conv)
+		s.types[typ] = true
+	}
+}
+
+// WithAWSConfig sets the aws config used to create the SSM client, instead of
+// loading the default config. It has no effect if WithClient was passed.
+func WithAWSConfig(cfg aws.Config) Option {
+	return func(s *ParamStore) {
+		s.awsConfig = &cfg
+	}
+}
+
+// WithRegion sets the region to read parameters from, overriding the region in
+// the aws config. It has no effect if WithClient was passed.
+func WithRegion(region string) Option {
+	return func(s *ParamStore) {
+		s.region = region
+	}
+}
+
+// WithEndpoint sets the URL of the SSM endpoint, for example to use a local
+// emulator such as LocalStack:
+//
+//   WithEndpoint("http://localhost:4566")
+//
+// It has no effect if WithClient was passed.
+func WithEndpoint(url string) Option {
+	return func(s *ParamStore) {
+		s.endpoint = url
+	}
+}
+
+// WithHTTPClient sets the HTTP client used by the SSM client. It has no effect
+// if WithClient was passed.
+func WithHTTPClient(client aws.HTTPClient) Option {
+	return func(s *ParamStore) {
+		s.httpClient = client
+	}
+}
+
+// WithAPIOptions adds middleware to every request made to SSM, for example to
+// set custom headers or add instrumentation:
+//
+//   WithAPIOptions(func(stack *middleware.Stack) error {
+//       return stack.Build.Add(setHeader, middleware.After)
+//   })
+//
+// The options are passed to each call of the Client, so they also apply to a
+// client passed to WithClient, as long as it is an *ssm.Client from
+// aws-sdk-go-v2.
+func WithAPIOptions(fns ...func(*middleware.Stack) error) Option {
+	return func(s *ParamStore) {
+		s.apiOptions = append(s.apiOptions, fns...)
+	}
+}
+
+// WithLogger sets the logger to use. A summary of each read is logged at
+// level INFO and the name and version of each parameter at level DEBUG. Values
+// are never logged.
+//
+// By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *ParamStore) {
+		s.logger = logger
+	}
+}
+
 // WithClient sets the SSM client to use.
 func WithClient(client Client) Option {
 	return func(s *ParamStore) {
@@ -167,71 +631,258 @@ func WithClient(client Client) Option {
 //
 // The target must be a non-nil pointer to a struct.
 func (s *ParamStore) Read(ctx context.Context, target interface{}) error {
+	val, err := targetValue(target)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.read(ctx, val, s.prefix)
+	return err
+}
+
+// read reads the parameters under prefix into val, returning the schema of
+// val and the parameters read.
+func (s *ParamStore) read(ctx context.Context, val reflect.Value, prefix string) (map[string][][]int, []types.Parameter, error) {
+	start := time.Now()
+
+	schema, err := s.schema(val.Type(), prefix, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, err = s.withReadOptions(ctx, val.Type(), prefix, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params, err := s.fetchStale(s.withBudget(ctx), sortedNames(schema))
+	unread, partial := unreadNames(err)
+	if err != nil && !partial {
+		return nil, nil, fmt.Errorf("read ssm: %v", err)
+	}
+	if partial {
+		// The other fields are set, so the config can be used with the
+		// error.
+		rest := make(map[string][][]int, len(schema))
+		for _, name := range without(sortedNames(schema), unread) {
+			rest[name] = schema[name]
+		}
+		if err := s.assign(ctx, val, rest, params); err != nil {
+			return nil, nil, err
+		}
+		if perr, ok := err.(PartialError); ok {
+			return nil, nil, perr.withFields(val.Type(), schema)
+		}
+		return nil, nil, err
+	}
+	if err := s.assign(ctx, val, schema, params); err != nil {
+		return nil, nil, err
+	}
+	s.record(ctx, val.Type(), prefix, params)
+	s.recordWatermarks(ctx, params)
+
+	s.log(ctx, slog.LevelInfo, "read parameters",
+		slog.Int("count", len(params)),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return schema, params, nil
+}
+
+// targetValue returns the struct that target points to.
+func targetValue(target interface{}) (reflect.Value, error) {
 	val := reflect.ValueOf(target)
 	if val.Kind() != reflect.Ptr {
-		return fmt.Errorf("target is not a pointer")
+		return reflect.Value{}, fmt.Errorf("target is not a pointer")
 	}
 	if val.IsNil() {
-		return fmt.Errorf("target is a nil pointer")
+		return reflect.Value{}, fmt.Errorf("target is a nil pointer")
 	}
 	val = val.Elem()
 	if val.Kind() != reflect.Struct {
-		return fmt.Errorf("target is not a pointer to a struct")
-	}
-	ty := val.Type()
-
-	schema, err := s.schema(ty, s.prefix, nil)
-	if err != nil {
-		return err
+		return reflect.Value{}, fmt.Errorf("target is not a pointer to a struct")
 	}
+	return val, nil
+}
 
+// sortedNames returns the parameter names in the schema, sorted.
+func sortedNames(schema map[string][][]int) []string {
 	names := make([]string, 0, len(schema))
 	for n := range schema {
 		names = append(names, n)
 	}
+	sort.Strings(names)
+	return names
+}
 
-	input := &ssm.GetParametersInput{
-		Names:          names,
-		WithDecryption: aws.Bool(true),
+// assign sets the values of params to the fields in val. A NotFoundError is
+// returned if a parameter in the schema is not in params.
+func (s *ParamStore) assign(ctx context.Context, val reflect.Value, schema map[string][][]int, params []types.Parameter) error {
+	params = s.dropEmpty(params)
+	if err := s.validate(val.Type(), schema, params); err != nil {
+		return err
 	}
-	resp, err := s.cli.GetParametersRequest(input).Send(ctx)
-	if err != nil {
-		return fmt.Errorf("read ssm: %v", err)
+	found := make(map[string]bool, len(params))
+	paths, _ := splitPaths(sortedNames(schema))
+	for _, path := range paths {
+		for _, index := range schema[path] {
+			m, err := s.makeMap(ctx, path, params, structField(val.Type(), index).Type)
+			if err != nil {
+				return s.paramError(ctx, path, err)
+			}
+			if m.Len() > 0 {
+				allocField(val, index).Set(m)
+				found[path] = true
+			}
+		}
+		if found[path] {
+			s.mu.Lock()
+			s.paramTypes[path] = pathType(path, params)
+			s.mu.Unlock()
+			s.warnDeprecated(ctx, val.Type(), path, schema[path])
+		}
 	}
-
-	for _, param := range resp.Parameters {
+	for _, param := range params {
 		name := *param.Name
-		index := schema[name]
-		delete(schema, name)
-		field := val
-		for _, i := range index {
-			field = field.Field(i)
-			if field.Kind() == reflect.Ptr && field.IsNil() {
+		indices, ok := schema[name]
+		if !ok {
+			continue
+		}
+		found[name] = true
+		for _, index := range indices {
+			if err := s.setField(ctx, param, val, index); err != nil {
+				return s.paramError(ctx, name, err)
+			}
+		}
+		s.mu.Lock()
+		s.paramTypes[name] = param.Type
+		s.mu.Unlock()
+		s.log(ctx, slog.LevelDebug, "read parameter",
+			slog.String("name", name),
+			slog.Int64("version", param.Version),
+		)
+		s.warnDeprecated(ctx, val.Type(), name, indices)
+	}
+	if len(found) < len(schema) {
+		var missing []string
+		for _, name := range sortedNames(schema) {
+			if found[name] {
+				continue
+			}
+			required, err := s.setDefaults(ctx, val, name, schema[name])
+			if err != nil {
+				return s.paramError(ctx, name, err)
+			}
+			if required {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			if err := s.reportMissing(ctx, missing); err != nil {
+				return err
+			}
+		}
+	}
+	s.notifyChanges(ctx, val.Type(), schema, params)
+	return nil
+}
+
+// allocField returns the nested field at index in val. Pointers are only
+// allocated when a value is set. Existing pointers are reused.
+func allocField(val reflect.Value, index []int) reflect.Value {
+	field := val
+	for _, i := range index {
+		field = field.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
 				field.Set(reflect.New(field.Type().Elem()))
-				field = field.Elem()
 			}
+			field = field.Elem()
+		}
+	}
+	return field
+}
+
+// setDefaults sets the fields of a parameter that does not exist to the value
+// of their default= tag option. It reports whether the parameter is required,
+// as a field has neither a default nor the optional tag option.
+func (s *ParamStore) setDefaults(ctx context.Context, val reflect.Value, name string, indices [][]int) (bool, error) {
+	required := false
+	for _, index := range indices {
+		f := structField(val.Type(), index)
+		tag, _ := s.lookupTag(f)
+		_, opts := parseTag(tag)
+		def, ok := opts.Get("default")
+		if !ok {
+			if !opts.Contains("optional") {
+				required = true
+			}
+			recordSource(ctx, SourceMissing, name)
+			continue
 		}
-		if err := s.setValue(param, field); err != nil {
-			return fmt.Errorf("%s: %v", *param.Name, err)
+		recordSource(ctx, SourceDefault, name)
+		param := types.Parameter{
+			Name:  aws.String(name),
+			Type:  paramType(f.Type),
+			Value: aws.String(def),
+		}
+		if _, ok := opts.Get("sep"); ok || opts.Contains("json") || opts.Contains("kv") {
+			param.Type = types.ParameterTypeString
+		}
+		if err := s.setField(ctx, param, val, index); err != nil {
+			return false, fmt.Errorf("default: %v", err)
 		}
 	}
-	if len(schema) > 0 {
-		// Items were not deleted -> not found
-		names = make([]string, 0, len(schema))
-		for n := range schema {
-			names = append(names, n)
+	return required, nil
+}
+
+// log logs a message if a logger was set.
+func (s *ParamStore) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if s.logger == nil {
+		return
+	}
+	s.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// paramError annotates err with the parameter name and, if WithDescriptions
+// was passed, the description of the parameter.
+func (s *ParamStore) paramError(ctx context.Context, name string, err error) error {
+	if s.descriptions {
+		if desc := s.describe(ctx, name); desc != "" {
+			return fmt.Errorf("%s: %v (%s)", name, err, desc)
 		}
-		return NotFoundError{names: names}
 	}
+	return fmt.Errorf("%s: %v", name, err)
+}
 
-	return nil
+// describe returns the description of a parameter. Errors are ignored as the
+// description is only used to add context to another error.
+func (s *ParamStore) describe(ctx context.Context, name string) string {
+	// DescribeParameters does not accept version or label selectors.
+	name, _ = splitSelector(name)
+	input := &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{{
+			Key:    aws.String("Name"),
+			Option: aws.String("Equals"),
+			Values: []string{name},
+		}},
+	}
+	var resp *ssm.DescribeParametersOutput
+	err := s.send(ctx, func() (err error) {
+		resp, err = s.cli.DescribeParameters(ctx, input, s.clientOptions()...)
+		return err
+	})
+	if err != nil || len(resp.Parameters) == 0 || resp.Parameters[0].Description == nil {
+		return ""
+	}
+	return *resp.Parameters[0].Description
 }
 
-func (s *ParamStore) setValue(p ssm.Parameter, v reflect.Value) error {
+func (s *ParamStore) setValue(ctx context.Context, p types.Parameter, v reflect.Value) error {
 	ty := v.Type()
+	if err := s.checkDataType(p, ty); err != nil {
+		return err
+	}
 
 	for _, conv := range s.converters {
-		ok, err := conv(p, v)
+		ok, err := conv(ctx, p, v)
 		if err != nil {
 			return err
 		}
@@ -240,16 +891,38 @@ func (s *ParamStore) setValue(p ssm.Parameter, v reflect.Value) error {
 		}
 	}
 
+	if ok, err := setNull(p, v); ok || err != nil {
+		return err
+	}
+	if ok, err := setURL(p, v); ok || err != nil {
+		return err
+	}
+	if ok, err := setIPNet(p, v); ok || err != nil {
+		return err
+	}
+	if ok, err := setBigFloat(p, v); ok || err != nil {
+		return err
+	}
+	if ok, err := setText(p, v); ok || err != nil {
+		return err
+	}
+	if ok, err := setJSONUnmarshaler(p, v); ok || err != nil {
+		return err
+	}
+	if ok, err := setBinary(p, v); ok || err != nil {
+		return err
+	}
+
 	switch ty.Kind() {
 	case reflect.String:
 		switch p.Type {
-		case ssm.ParameterTypeString, ssm.ParameterTypeSecureString:
+		case types.ParameterTypeString, types.ParameterTypeSecureString:
 			v.SetString(*p.Value)
 		default:
 			return fmt.Errorf("cannot assign %s to %s", p.Type, ty)
 		}
 	case reflect.Slice:
-		if p.Type != ssm.ParameterTypeStringList {
+		if p.Type != types.ParameterTypeStringList {
 			// Technically this would work, but we don't allow implicitly
 			// converting the value.
 			return fmt.Errorf("cannot set %s to %s", p.Type, v.Type())
@@ -258,51 +931,591 @@ func (s *ParamStore) setValue(p ssm.Parameter, v reflect.Value) error {
 		n := len(parts)
 		slice := reflect.MakeSlice(ty, n, n)
 		for i, part := range parts {
-			sliceParam := ssm.Parameter{
-				Type:  ssm.ParameterTypeString,
+			sliceParam := types.Parameter{
+				Type:  types.ParameterTypeString,
 				Value: aws.String(part),
 			}
-			if err := s.setValue(sliceParam, slice.Index(i)); err != nil {
+			if err := s.setValue(ctx, sliceParam, slice.Index(i)); err != nil {
 				return fmt.Errorf("set slice index %d: %v", i, err)
 			}
 		}
 		v.Set(slice)
+	case reflect.Ptr:
+		// Slice items, such as []*string
+		if v.IsNil() {
+			v.Set(reflect.New(ty.Elem()))
+		}
+		return s.setValue(ctx, p, v.Elem())
 	default:
 		return fmt.Errorf("unsupported: %s", ty.Kind())
 	}
 	return nil
 }
 
-func (s *ParamStore) schema(t reflect.Type, keyPrefix string, index []int) (map[string][]int, error) {
-	m := make(map[string][]int)
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		name, ok := f.Tag.Lookup(s.tag)
+// setField sets the field at index to the value of the parameter. The sep=
+// tag option splits a String parameter into a slice by a custom separator,
+// for values that contain commas:
+//
+//   Hosts []string `ssm:"hosts,sep=;"`
+//
+// The conversion is recorded if WithConversionReport was passed.
+func (s *ParamStore) setField(ctx context.Context, p types.Parameter, val reflect.Value, index []int) error {
+	f := structField(val.Type(), index)
+	tag, _ := s.lookupTag(f)
+	_, opts := parseTag(tag)
+	var v reflect.Value
+	if f.Type.Kind() == reflect.Ptr && s.types[f.Type] {
+		// Converters for pointer types set the pointer, instead of the
+		// value it points to
+		v = allocField(val, index[:len(index)-1]).Field(index[len(index)-1])
+	} else {
+		v = allocField(val, index)
+	}
+	if s.conversionReport {
+		s.recordConversion(ctx, p, val.Type(), index, opts)
+	}
+	if opts.Contains("json") {
+		return setJSON(p, v)
+	}
+	sep, ok := opts.Get("sep")
+	if opts.Contains("kv") {
 		if !ok {
-			continue
+			sep = ","
 		}
-		if f.PkgPath != "" {
-			return nil, fmt.Errorf("field %q must be exported", f.Name)
+		return s.setPairs(ctx, p, v, sep)
+	}
+	if !ok {
+		return s.setValue(ctx, p, v)
+	}
+	if sep == "" {
+		return fmt.Errorf("empty sep")
+	}
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("sep requires a slice, not %s", v.Type())
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return fmt.Errorf("cannot split %s by %q", p.Type, sep)
+	}
+	parts := strings.Split(*p.Value, sep)
+	slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		item := types.Parameter{
+			Type:  types.ParameterTypeString,
+			Value: aws.String(part),
 		}
-		name = keyPrefix + "/" + name
+		if err := s.setValue(ctx, item, slice.Index(i)); err != nil {
+			return fmt.Errorf("set slice index %d: %v", i, err)
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
+// setJSON decodes the JSON document in the parameter into v. The document is
+// decoded into a new value, so fields and keys not in the document are not
+// kept from a previous read.
+func setJSON(p types.Parameter, v reflect.Value) error {
+	if p.Type == types.ParameterTypeStringList {
+		return fmt.Errorf("cannot decode %s as json", p.Type)
+	}
+	ptr := reflect.New(v.Type())
+	if err := json.Unmarshal([]byte(*p.Value), ptr.Interface()); err != nil {
+		return fmt.Errorf("decode json: %v", err)
+	}
+	v.Set(ptr.Elem())
+	return nil
+}
+
+// setPairs sets the map v from a parameter holding key=value pairs separated
+// by sep, or a JSON object:
+//
+//   Limits map[string]int `ssm:"limits,kv"` // api=100,batch=10 or {"api": 100}
+//
+// Conversion rules apply to the values. JSON strings are converted from their
+// value, and other JSON values, such as numbers, from their JSON text.
+func (s *ParamStore) setPairs(ctx context.Context, p types.Parameter, v reflect.Value, sep string) error {
+	ty := v.Type()
+	if ty.Key().Kind() != reflect.String {
+		return fmt.Errorf("unsupported map key: %s", ty.Key())
+	}
+	if sep == "" {
+		return fmt.Errorf("empty sep")
+	}
+	entries := make(map[string]string)
+	switch value := strings.TrimSpace(*p.Value); {
+	case p.Type != types.ParameterTypeStringList && strings.HasPrefix(value, "{"):
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(value), &obj); err != nil {
+			return fmt.Errorf("decode json: %v", err)
+		}
+		for k, raw := range obj {
+			var str string
+			if err := json.Unmarshal(raw, &str); err != nil {
+				str = string(raw)
+			}
+			entries[k] = str
+		}
+	case value == "":
+	case p.Type == types.ParameterTypeStringList && sep != ",":
+		return fmt.Errorf("cannot split %s by %q", p.Type, sep)
+	default:
+		for _, pair := range strings.Split(*p.Value, sep) {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("%q is not a key=value pair", pair)
+			}
+			if _, dup := entries[k]; dup {
+				return fmt.Errorf("duplicate key %q", k)
+			}
+			entries[k] = val
+		}
+	}
+	m := reflect.MakeMapWithSize(ty, len(entries))
+	for k, val := range entries {
+		item := types.Parameter{
+			Type:  types.ParameterTypeString,
+			Value: aws.String(val),
+		}
+		elem := reflect.New(ty.Elem()).Elem()
+		if err := s.setValue(ctx, item, elem); err != nil {
+			return fmt.Errorf("set map key %s: %v", k, err)
+		}
+		m.SetMapIndex(reflect.ValueOf(k).Convert(ty.Key()), elem)
+	}
+	v.Set(m)
+	return nil
+}
+
+// nullTypes are the database/sql Null types set by setNull.
+var nullTypes = map[reflect.Type]bool{
+	reflect.TypeOf(sql.NullString{}):  true,
+	reflect.TypeOf(sql.NullInt64{}):   true,
+	reflect.TypeOf(sql.NullInt32{}):   true,
+	reflect.TypeOf(sql.NullInt16{}):   true,
+	reflect.TypeOf(sql.NullByte{}):    true,
+	reflect.TypeOf(sql.NullFloat64{}): true,
+	reflect.TypeOf(sql.NullBool{}):    true,
+	reflect.TypeOf(sql.NullTime{}):    true,
+}
+
+// setNull sets v if it is one of the database/sql Null types. Valid is always
+// set to true, as the parameter exists. sql.NullBool accepts the same values
+// as WithParseBool, and sql.NullTime is parsed as RFC 3339.
+func setNull(p types.Parameter, v reflect.Value) (bool, error) {
+	if !v.CanAddr() || !nullTypes[v.Type()] {
+		return false, nil
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return false, fmt.Errorf("cannot assign %s to %s", p.Type, v.Type())
+	}
+	switch n := v.Addr().Interface().(type) {
+	case *sql.NullString:
+		n.String = *p.Value
+		n.Valid = true
+	case *sql.NullInt64:
+		num, err := parseNullInt(*p.Value, 64)
+		if err != nil {
+			return false, err
+		}
+		n.Int64 = num
+		n.Valid = true
+	case *sql.NullInt32:
+		num, err := parseNullInt(*p.Value, 32)
+		if err != nil {
+			return false, err
+		}
+		n.Int32 = int32(num)
+		n.Valid = true
+	case *sql.NullInt16:
+		num, err := parseNullInt(*p.Value, 16)
+		if err != nil {
+			return false, err
+		}
+		n.Int16 = int16(num)
+		n.Valid = true
+	case *sql.NullByte:
+		num, err := strconv.ParseUint(*p.Value, 10, 8)
+		if err != nil {
+			nerr := err.(*strconv.NumError)
+			return false, fmt.Errorf("parse %q as uint: %v", nerr.Num, nerr.Err)
+		}
+		n.Byte = byte(num)
+		n.Valid = true
+	case *sql.NullFloat64:
+		f, err := strconv.ParseFloat(*p.Value, 64)
+		if err != nil {
+			nerr := err.(*strconv.NumError)
+			return false, fmt.Errorf("parse %q as float: %v", nerr.Num, nerr.Err)
+		}
+		n.Float64 = f
+		n.Valid = true
+	case *sql.NullBool:
+		b, err := parseBool(*p.Value)
+		if err != nil {
+			return false, err
+		}
+		n.Bool = b
+		n.Valid = true
+	case *sql.NullTime:
+		t, err := time.Parse(time.RFC3339, *p.Value)
+		if err != nil {
+			return false, fmt.Errorf("parse %q as time: %v", *p.Value, err)
+		}
+		n.Time = t
+		n.Valid = true
+	}
+	return true, nil
+}
+
+// parseNullInt parses value as an int of the given bit size.
+func parseNullInt(value string, bits int) (int64, error) {
+	num, err := strconv.ParseInt(value, 10, bits)
+	if err != nil {
+		nerr := err.(*strconv.NumError)
+		return 0, fmt.Errorf("parse %q as int: %v", nerr.Num, nerr.Err)
+	}
+	return num, nil
+}
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// setURL sets v if it is a url.URL. The URL must be absolute. url.URL
+// implements encoding.BinaryUnmarshaler, but is stored as text.
+func setURL(p types.Parameter, v reflect.Value) (bool, error) {
+	if v.Type() != urlType {
+		return false, nil
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return false, fmt.Errorf("cannot assign %s to %s", p.Type, v.Type())
+	}
+	u, err := url.Parse(*p.Value)
+	if err != nil {
+		return false, err
+	}
+	if u.Scheme == "" {
+		return false, fmt.Errorf("parse %q as url: missing scheme", *p.Value)
+	}
+	v.Set(reflect.ValueOf(*u))
+	return true, nil
+}
+
+var ipNetType = reflect.TypeOf(net.IPNet{})
+
+// setIPNet sets v if it is a net.IPNet, parsed from a CIDR block such as
+// 10.0.0.0/16. net.IP, netip.Addr and netip.Prefix implement
+// encoding.TextUnmarshaler, but net.IPNet does not.
+func setIPNet(p types.Parameter, v reflect.Value) (bool, error) {
+	if v.Type() != ipNetType {
+		return false, nil
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return false, fmt.Errorf("cannot assign %s to %s", p.Type, v.Type())
+	}
+	_, n, err := net.ParseCIDR(*p.Value)
+	if err != nil {
+		return false, err
+	}
+	v.Set(reflect.ValueOf(*n))
+	return true, nil
+}
+
+var bigFloatType = reflect.TypeOf(big.Float{})
+
+// setBigFloat sets v if it is a big.Float, with enough precision to hold
+// every digit of the value. big.Float implements encoding.TextUnmarshaler, but
+// UnmarshalText rounds the value to 64 bits of precision.
+func setBigFloat(p types.Parameter, v reflect.Value) (bool, error) {
+	if v.Type() != bigFloatType {
+		return false, nil
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return false, fmt.Errorf("cannot assign %s to %s", p.Type, v.Type())
+	}
+	// Each decimal digit needs log2(10) < 4 bits
+	prec := uint(len(*p.Value)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+	f, _, err := big.ParseFloat(*p.Value, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return false, fmt.Errorf("parse %q as big.Float: %v", *p.Value, err)
+	}
+	v.Set(reflect.ValueOf(f).Elem())
+	return true, nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isText reports whether values of type t are set with UnmarshalText.
+func isText(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// setText sets v if it implements encoding.TextUnmarshaler. This is checked
+// before encoding.BinaryUnmarshaler, as types implementing both, such as
+// time.Time, are stored as text in parameters.
+func setText(p types.Parameter, v reflect.Value) (bool, error) {
+	if !v.CanAddr() || !isText(v.Type()) {
+		return false, nil
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return false, fmt.Errorf("cannot assign %s to %s", p.Type, v.Type())
+	}
+	if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(*p.Value)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// isJSON reports whether values of type t are set with UnmarshalJSON.
+func isJSON(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(jsonUnmarshalerType)
+}
+
+// setJSONUnmarshaler sets v if it implements json.Unmarshaler. Values that
+// are not valid JSON are passed as a JSON string, so types such as enums,
+// which expect a quoted string, can be read from a plain value.
+func setJSONUnmarshaler(p types.Parameter, v reflect.Value) (bool, error) {
+	if !v.CanAddr() || !isJSON(v.Type()) {
+		return false, nil
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return false, fmt.Errorf("cannot assign %s to %s", p.Type, v.Type())
+	}
+	data := []byte(*p.Value)
+	if !json.Valid(data) {
+		data, _ = json.Marshal(*p.Value)
+	}
+	if err := v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+var binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+
+// setBinary sets v if it implements encoding.BinaryUnmarshaler. The parameter
+// value is decoded as base64, see decodeBase64.
+func setBinary(p types.Parameter, v reflect.Value) (bool, error) {
+	if !v.CanAddr() || !v.Addr().Type().Implements(binaryUnmarshalerType) {
+		return false, nil
+	}
+	if p.Type == types.ParameterTypeStringList {
+		return false, fmt.Errorf("cannot assign %s to %s", p.Type, v.Type())
+	}
+	data, err := decodeBase64(*p.Value)
+	if err != nil {
+		return false, fmt.Errorf("decode base64: %v", err)
+	}
+	if err := v.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding.
+// Line breaks are ignored, so the output of tools that wrap long lines, such
+// as base64, can be stored as is.
+func decodeBase64(value string) ([]byte, error) {
+	value = strings.TrimRight(strings.TrimSpace(value), "=")
+	if strings.ContainsAny(value, "-_") {
+		return base64.RawURLEncoding.DecodeString(value)
+	}
+	return base64.RawStdEncoding.DecodeString(value)
+}
+
+// isNested reports whether t is a struct whose fields are read as separate
+// parameters, as opposed to a struct that is read from a single parameter.
+func (s *ParamStore) isNested(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || s.types[t] || s.types[reflect.PtrTo(t)] {
+		return false
+	}
+	if isText(t) || isJSON(t) || reflect.PtrTo(t).Implements(binaryUnmarshalerType) {
+		return false
+	}
+	if nullTypes[t] {
+		return false
+	}
+	switch t {
+	case reflect.TypeOf(time.Time{}), ipNetType:
+		return false
+	}
+	return true
+}
+
+// schema returns the indices of the fields to read each parameter into. A
+// parameter may be read into several fields.
+func (s *ParamStore) schema(t reflect.Type, keyPrefix string, index []int) (map[string][][]int, error) {
+	schema, err := s.buildSchema(t, keyPrefix, keyPrefix, index, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNames(sortedNames(schema)); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// buildSchema builds the schema of t. root is the prefix that names starting
+// with ~/ are relative to. fieldPath holds the names of the parent fields
+// whose parameter name is derived by the key func, which are passed to the
+// key func along with the name of the field.
+func (s *ParamStore) buildSchema(t reflect.Type, root, keyPrefix string, index []int, fieldPath []string) (map[string][][]int, error) {
+	m := make(map[string][][]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
 		ty := f.Type
 		if ty.Kind() == reflect.Ptr {
 			ty = ty.Elem()
 		}
+		tag, ok := s.lookupTag(f)
+		name, opts := parseTag(tag)
+		// Values decoded from a JSON document are read from a single
+		// parameter, whatever their type.
+		decodeJSON := opts.Contains("json")
+		// Maps with the kv option are read from key=value pairs in a single
+		// parameter, instead of one parameter per key.
+		pairs := opts.Contains("kv")
+		if pairs && ty.Kind() != reflect.Map {
+			return nil, fmt.Errorf("field %q: kv requires a map", f.Name)
+		}
+		nested := s.isNested(ty) && !decodeJSON
+		embedded := f.Anonymous && nested
+		if !ok && !embedded || tag == "-" {
+			// Fields tagged - are skipped, like with encoding/json. The
+			// name - can be used with "-,".
+			continue
+		}
+		if f.PkgPath != "" && (!embedded || f.Type.Kind() == reflect.Ptr) {
+			// The fields of an unexported embedded struct can be set, but
+			// a pointer to it can not be allocated.
+			return nil, fmt.Errorf("field %q must be exported", f.Name)
+		}
+		parent := keyPrefix
+		if strings.HasPrefix(name, "~/") || opts.Contains("root") {
+			// Anchored at the prefix, instead of the parent struct
+			name = strings.TrimPrefix(name, "~/")
+			parent = root
+		}
+		var path []string
+		switch {
+		case embedded && name == "":
+			// Fields of embedded structs are promoted to the parent, like
+			// with encoding/json
+			name = parent
+			path = fieldPath
+		case opts.Contains("inline") || opts.Contains("squash"):
+			// Fields of the nested struct are read at the same level
+			if !nested {
+				return nil, fmt.Errorf("field %q: inline requires a struct", f.Name)
+			}
+			name = parent
+			path = fieldPath
+		case name == "" && !s.splitWords:
+			return nil, fmt.Errorf("field %q has no parameter name", f.Name)
+		case name == "" && s.keyFunc != nil && nested:
+			// The names of the nested fields are derived from their full
+			// path
+			name = parent
+			path = append(append([]string(nil), fieldPath...), f.Name)
+		case name == "" && s.keyFunc != nil:
+			path = append(append([]string(nil), fieldPath...), f.Name)
+			name = parent + "/" + strings.Trim(s.keyFunc(path), "/")
+		case name == "":
+			name = parent + "/" + snakeCase(f.Name)
+		default:
+			name = tagName(parent, name)
+		}
+
+		envs, ok, err := opts.Envs()
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", f.Name, err)
+		}
+		if ok && !s.inEnvironment(envs) {
+			continue
+		}
+		name, err = s.selectVersion(name, opts, !nested && (ty.Kind() != reflect.Map || decodeJSON || pairs))
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", f.Name, err)
+		}
 
-		if ty.Kind() == reflect.Struct && ty != reflect.TypeOf(time.Time{}) {
-			// time.Time is also a struct - needs special case
-			nested, err := s.schema(ty, name, append(index, i))
+		// Copied, as appending to index may otherwise overwrite the index
+		// of a previous field.
+		fieldIndex := append(append([]int(nil), index...), i)
+		if nested {
+			fields, err := s.buildSchema(ty, root, name, fieldIndex, path)
 			if err != nil {
 				return nil, err
 			}
-			for k, v := range nested {
-				m[k] = v
+			for k, v := range fields {
+				m[k] = append(m[k], v...)
 			}
 			continue
 		}
-		m[name] = append(index, i)
-
+		if !s.inFieldMask(name) {
+			continue
+		}
+		if ty.Kind() == reflect.Map && !decodeJSON && !pairs {
+			name += "/"
+		}
+		m[name] = append(m[name], fieldIndex)
 	}
 	return m, nil
 }
+
+// tagName returns the parameter name of a name in a struct tag, under the
+// name of the parent struct unless it is absolute.
+func tagName(parent, name string) string {
+	switch {
+	case isARN(name):
+		// Shared parameter
+		return name
+	case strings.HasPrefix(name, "/"):
+		// Absolute name, not under the prefix or parent struct
+		return strings.TrimSuffix(name, "/")
+	}
+	return parent + "/" + name
+}
+
+// lookupTag returns the struct tag of the field. If WithProtoNames was passed,
+// the name in the protobuf tag is used for fields without a struct tag. If
+// WithAutoKeys was passed, exported fields without a struct tag have an empty
+// tag.
+func (s *ParamStore) lookupTag(f reflect.StructField) (string, bool) {
+	tag, ok := f.Tag.Lookup(s.tag)
+	if ok {
+		return tag, true
+	}
+	if s.protoNames {
+		if proto, ok := f.Tag.Lookup("protobuf"); ok {
+			for _, part := range strings.Split(proto, ",") {
+				if strings.HasPrefix(part, "name=") {
+					return strings.TrimPrefix(part, "name="), true
+				}
+			}
+			return "", false
+		}
+	}
+	if s.autoKeys && f.PkgPath == "" {
+		return "", true
+	}
+	return "", false
+}
+
+// inFieldMask reports whether the parameter is included by the field mask.
+func (s *ParamStore) inFieldMask(name string) bool {
+	if s.fieldMask == nil {
+		return true
+	}
+	name, _ = splitSelector(name)
+	path := strings.TrimPrefix(strings.TrimPrefix(name, s.prefix+"/"), "/")
+	path = strings.Replace(path, "/", ".", -1)
+	for _, m := range s.fieldMask {
+		if path == m || strings.HasPrefix(path, m+".") {
+			return true
+		}
+	}
+	return false
+}
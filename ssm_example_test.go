@@ -3,6 +3,8 @@ package ssm_test
 import (
 	"context"
 	"log"
+	"net/url"
+	"reflect"
 	"time"
 
 	"github.com/akupila/ssm"
@@ -149,3 +151,130 @@ func ExampleWithTag() {
 		log.Fatal(err)
 	}
 }
+
+func ExampleWithConverter() {
+	type Config struct {
+		Endpoint url.URL `ssm:"endpoint"`
+	}
+
+	params, err := ssm.NewParamStore(
+		ssm.WithConverter(reflect.TypeOf(url.URL{}), func(v string) (interface{}, error) {
+			u, err := url.Parse(v)
+			if err != nil {
+				return nil, err
+			}
+			return *u, nil
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var cfg Config
+	if err := params.Read(context.Background(), &cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleWithSplitWords() {
+	type Config struct {
+		ClientID     string `ssm:""`
+		ClientSecret string `ssm:""`
+	}
+
+	params, err := ssm.NewParamStore(
+		ssm.WithSplitWords(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var cfg Config
+	if err := params.Read(context.Background(), &cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// cfg.ClientID and cfg.ClientSecret are read from /client_id and
+	// /client_secret
+}
+
+func ExampleWithAutoKeys() {
+	type Config struct {
+		ClientID     string
+		ClientSecret string
+	}
+
+	params, err := ssm.NewParamStore(
+		ssm.WithAutoKeys(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var cfg Config
+	if err := params.Read(context.Background(), &cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// cfg.ClientID and cfg.ClientSecret are read from /client_id and
+	// /client_secret
+}
+
+func ExampleWithPathFetch() {
+	type Config struct {
+		User string `ssm:"user"`
+		Pass string `ssm:"pass"`
+		Host string `ssm:"host"`
+	}
+
+	params, err := ssm.NewParamStore(
+		ssm.WithPrefix("dev/db"),
+		ssm.WithPathFetch(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var cfg Config
+	if err := params.Read(context.Background(), &cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// All parameters under /dev/db were read with GetParametersByPath
+}
+
+func ExampleParamStore_SubscribeValue() {
+	params, err := ssm.NewParamStore(
+		ssm.WithPrefix("prod"),
+		ssm.WithPollInterval(10*time.Second),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	values, err := params.SubscribeValue(ctx, "kill_switch")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for v := range values {
+		log.Printf("Kill switch: %s", v)
+	}
+}
+
+func ExampleParamStore_WriteMap() {
+	params, err := ssm.NewParamStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	values := map[string]string{
+		"host":    "localhost",
+		"db/user": "alice",
+	}
+	if err := params.WriteMap(context.Background(), "dev", values, ssm.WithOverwrite()); err != nil {
+		log.Fatal(err)
+	}
+
+	// /dev/host and /dev/db/user are now set in ssm parameter store
+}
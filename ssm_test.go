@@ -1,16 +1,33 @@
 package ssm
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -18,14 +35,16 @@ func TestParamStore_Read(t *testing.T) {
 	tests := []struct {
 		name    string
 		options []Option
-		params  []ssm.Parameter
-		config  reflect.Type
-		want    []value
-		wantErr bool
+		params  []types.Parameter
+		// selectors holds the version selected by name:label.
+		selectors map[string]int64
+		config    reflect.Type
+		want      []value
+		wantErr   bool
 	}{
 		{
 			name: "String",
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/foo", "bar"),
 			},
 			config: reflect.TypeOf(struct {
@@ -37,7 +56,7 @@ func TestParamStore_Read(t *testing.T) {
 		},
 		{
 			name: "StringList",
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringListParam("/foo", "a,b,c"),
 			},
 			config: reflect.TypeOf(struct {
@@ -49,7 +68,7 @@ func TestParamStore_Read(t *testing.T) {
 		},
 		{
 			name: "SecureString",
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				secureStringParam("/foo", "foo"),
 			},
 			config: reflect.TypeOf(struct {
@@ -62,7 +81,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionPrefix",
 			options: []Option{WithPrefix("dev")},
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/dev/foo", "abc"),
 				stringParam("/prod/foo", "def"),
 			},
@@ -76,7 +95,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionPrefix_SlashPrefix",
 			options: []Option{WithPrefix("/dev")}, // trim /
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/dev/foo", "abc"),
 			},
 			config: reflect.TypeOf(struct {
@@ -89,7 +108,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionPrefix_SlashSuffix",
 			options: []Option{WithPrefix("dev/")}, // trim /
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/dev/foo", "abc"),
 			},
 			config: reflect.TypeOf(struct {
@@ -102,7 +121,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionTag",
 			options: []Option{WithTag("config")},
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/foo", "abc"),
 				stringParam("/bar", "123"),
 			},
@@ -115,10 +134,246 @@ func TestParamStore_Read(t *testing.T) {
 				// Bar was not set
 			},
 		},
+		{
+			name:    "OptionSplitWords",
+			options: []Option{WithSplitWords()},
+			params: []types.Parameter{
+				stringParam("/client_id", "abc"),
+				stringParam("/api_key", "def"),
+				stringParam("/db/host_name", "ghi"),
+				stringParam("/explicit", "jkl"),
+			},
+			config: reflect.TypeOf(struct {
+				ClientID string `ssm:""`
+				APIKey   string `ssm:",unknown"`
+				DB       struct {
+					HostName string `ssm:""`
+				} `ssm:"db"`
+				Named string `ssm:"explicit"`
+			}{}),
+			want: []value{
+				{path: "ClientID", value: "abc"},
+				{path: "APIKey", value: "def"},
+				{path: "DB.HostName", value: "ghi"},
+				{path: "Named", value: "jkl"},
+			},
+		},
+		{
+			name:    "OptionAutoKeys",
+			options: []Option{WithAutoKeys()},
+			params: []types.Parameter{
+				stringParam("/client_id", "abc"),
+				stringParam("/db/host_name", "def"),
+				stringParam("/explicit", "ghi"),
+			},
+			config: reflect.TypeOf(struct {
+				ClientID string
+				DB       struct {
+					HostName string
+				}
+				Named    string `ssm:"explicit"`
+				Optional string `ssm:",optional"`
+				internal string
+			}{}),
+			want: []value{
+				{path: "ClientID", value: "abc"},
+				{path: "DB.HostName", value: "def"},
+				{path: "Named", value: "ghi"},
+				{path: "Optional", value: ""},
+			},
+		},
+		{
+			name: "OptionKeyFunc",
+			options: []Option{WithKeyFunc(func(fieldPath []string) string {
+				return strings.ToLower(strings.Join(fieldPath, "-"))
+			})},
+			params: []types.Parameter{
+				stringParam("/clientid", "abc"),
+				stringParam("/db-hostname", "def"),
+				stringParam("/db-tls-cert", "ghi"),
+				stringParam("/cache/hostname", "jkl"),
+				stringParam("/explicit", "mno"),
+			},
+			config: reflect.TypeOf(struct {
+				ClientID string `ssm:""`
+				DB       struct {
+					HostName string `ssm:""`
+					TLS      struct {
+						Cert string `ssm:""`
+					} `ssm:""`
+				} `ssm:""`
+				Cache struct {
+					HostName string `ssm:""`
+				} `ssm:"cache"`
+				Named string `ssm:"explicit"`
+			}{}),
+			want: []value{
+				{path: "ClientID", value: "abc"},
+				{path: "DB.HostName", value: "def"},
+				{path: "DB.TLS.Cert", value: "ghi"},
+				{path: "Cache.HostName", value: "jkl"},
+				{path: "Named", value: "mno"},
+			},
+		},
+		{
+			name:    "OptionEnvironment",
+			options: []Option{WithEnvironment("staging")},
+			params: []types.Parameter{
+				stringParam("/host", "abc"),
+				stringParam("/replicas", "3"),
+				stringParam("/workers", "8"),
+				stringParam("/debug", "true"),
+			},
+			config: reflect.TypeOf(struct {
+				Host     string `ssm:"host"`
+				Replicas string `ssm:"replicas,envs='prod,staging'"`
+				Workers  string `ssm:"workers,envs=prod|staging"`
+				Debug    string `ssm:"debug,envs=dev"`
+				Prod     struct {
+					Shards string `ssm:"shards"`
+				} `ssm:"prod,envs=prod"`
+			}{}),
+			want: []value{
+				{path: "Host", value: "abc"},
+				{path: "Replicas", value: "3"},
+				{path: "Workers", value: "8"},
+				{path: "Debug", value: ""},
+				{path: "Prod.Shards", value: ""},
+			},
+		},
+		{
+			name:    "ErrEnvsUnquoted",
+			options: []Option{WithEnvironment("staging")},
+			params: []types.Parameter{
+				stringParam("/replicas", "3"),
+			},
+			config: reflect.TypeOf(struct {
+				Replicas string `ssm:"replicas,envs=prod,staging"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "EnvsOptions",
+			options: []Option{WithEnvironment("prod")},
+			params: []types.Parameter{
+				stringParam("/replicas", "3"),
+			},
+			config: reflect.TypeOf(struct {
+				Replicas string `ssm:"replicas,envs=prod,optional,default=1"`
+			}{}),
+			want: []value{
+				{path: "Replicas", value: "3"},
+			},
+		},
+		{
+			name: "EnvsWithoutEnvironment",
+			params: []types.Parameter{
+				stringParam("/host", "abc"),
+			},
+			config: reflect.TypeOf(struct {
+				Host     string `ssm:"host"`
+				Replicas string `ssm:"replicas,envs=prod"`
+			}{}),
+			want: []value{
+				{path: "Host", value: "abc"},
+				{path: "Replicas", value: ""},
+			},
+		},
+		{
+			name:    "Skip",
+			options: []Option{WithAutoKeys()},
+			params: []types.Parameter{
+				stringParam("/host", "abc"),
+				stringParam("/-", "def"),
+			},
+			config: reflect.TypeOf(struct {
+				Host    string
+				Skipped string `ssm:"-"`
+				Dash    string `ssm:"-,"`
+				Client  func() `ssm:"-"`
+				Nested  struct {
+					Port string `ssm:"port"`
+				} `ssm:"-"`
+				EmbeddedBase `ssm:"-"`
+			}{}),
+			want: []value{
+				{path: "Host", value: "abc"},
+				{path: "Skipped", value: ""},
+				{path: "Dash", value: "def"},
+				{path: "Nested.Port", value: ""},
+			},
+		},
+		{
+			name: "TagOptions",
+			params: []types.Parameter{
+				stringParam("/foo", "abc"),
+			},
+			config: reflect.TypeOf(struct {
+				Foo string `ssm:"foo,unknown"`
+			}{}),
+			want: []value{
+				{path: "Foo", value: "abc"},
+			},
+		},
+		{
+			name:    "OptionProtoNames",
+			options: []Option{WithProtoNames()},
+			params: []types.Parameter{
+				stringParam("/display_name", "foo"),
+				stringParam("/database/host_name", "bar"),
+				stringParam("/tagged", "baz"),
+			},
+			config: reflect.TypeOf(protoConfig{}),
+			want: []value{
+				{path: "DisplayName", value: "foo"},
+				{path: "Database.HostName", value: "bar"},
+				{path: "Tagged", value: "baz"},
+			},
+		},
+		{
+			name:    "OptionFieldMask",
+			options: []Option{WithFieldMask("a", "nested")},
+			params: []types.Parameter{
+				stringParam("/a", "1"),
+				stringParam("/nested/c", "2"),
+			},
+			config: reflect.TypeOf(struct {
+				A      string `ssm:"a"`
+				B      string `ssm:"b"`
+				Nested struct {
+					C string `ssm:"c"`
+				} `ssm:"nested"`
+			}{}),
+			want: []value{
+				{path: "A", value: "1"},
+				{path: "B", value: ""},
+				{path: "Nested.C", value: "2"},
+			},
+		},
+		{
+			name:    "OptionFieldMask_Nested",
+			options: []Option{WithPrefix("dev"), WithFieldMask("nested.d")},
+			params: []types.Parameter{
+				stringParam("/dev/nested/d", "1"),
+			},
+			config: reflect.TypeOf(struct {
+				A      string `ssm:"a"`
+				Nested struct {
+					C string `ssm:"c"`
+					D string `ssm:"d"`
+				} `ssm:"nested"`
+				NestedOther struct {
+					D string `ssm:"d"`
+				} `ssm:"nested_other"`
+			}{}),
+			want: []value{
+				{path: "Nested.D", value: "1"},
+			},
+		},
 		{
 			name:    "OptionParseDuration",
 			options: []Option{WithParseDuration()},
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/timeout", "5s"),
 				stringParam("/not_duration", "foo"),
 			},
@@ -134,7 +389,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionParseDurationErrInvalid",
 			options: []Option{WithParseDuration()},
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/timeout", "invalid duration"),
 			},
 			config: reflect.TypeOf(struct {
@@ -145,7 +400,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionParseTime",
 			options: []Option{WithParseTime(time.RFC3339)},
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/date", "2020-01-02T15:04:05Z"),
 				stringParam("/not_time", "foo"),
 			},
@@ -161,7 +416,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionParseTimeErr",
 			options: []Option{WithParseTime(time.RFC3339)},
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/date", "invalid time"),
 			},
 			config: reflect.TypeOf(struct {
@@ -172,7 +427,7 @@ func TestParamStore_Read(t *testing.T) {
 		{
 			name:    "OptionWithParseNumber",
 			options: []Option{WithParseNumber()},
-			params: []ssm.Parameter{
+			params: []types.Parameter{
 				stringParam("/a", "1"),
 				stringParam("/b", "2"),
 				stringParam("/c", "3"),
@@ -201,209 +456,1822 @@ func TestParamStore_Read(t *testing.T) {
 			},
 		},
 		{
-			name:    "OptionWithParseNumber_Slice",
-			options: []Option{WithParseNumber()},
-			params: []ssm.Parameter{
-				stringListParam("/ints", "1,2,3"),
-				stringListParam("/floats", "1.23,4.56,7.89"),
+			name:    "OptionWithParseBool",
+			options: []Option{WithParseBool()},
+			params: []types.Parameter{
+				stringParam("/a", "true"),
+				stringParam("/b", "No"),
+				stringParam("/c", "1"),
+				stringParam("/d", "YES"),
+				stringListParam("/flags", "true,0,yes,False"),
 			},
 			config: reflect.TypeOf(struct {
-				Ints   []int     `ssm:"ints"`
-				Floats []float64 `ssm:"floats"`
+				A     bool   `ssm:"a"`
+				B     bool   `ssm:"b"`
+				C     *bool  `ssm:"c"`
+				D     bool   `ssm:"d"`
+				Flags []bool `ssm:"flags"`
 			}{}),
 			want: []value{
-				{path: "Ints", value: []int{1, 2, 3}},
-				{path: "Floats", value: []float64{1.23, 4.56, 7.89}},
+				{path: "A", value: true},
+				{path: "B", value: false},
+				{path: "C", value: aws.Bool(true)},
+				{path: "D", value: true},
+				{path: "Flags", value: []bool{true, false, true, false}},
 			},
 		},
 		{
-			name: "SetPointer",
-			params: []ssm.Parameter{
-				stringParam("/foo", "bar"),
+			name: "Regexp",
+			params: []types.Parameter{
+				stringParam("/route", `^/users/(\d+)$`),
+				stringParam("/filter", "(?i)error"),
+				stringListParam("/ignore", "^health$,^metrics$"),
 			},
 			config: reflect.TypeOf(struct {
-				Foo *string `ssm:"foo"`
+				Route  *regexp.Regexp   `ssm:"route"`
+				Filter regexp.Regexp    `ssm:"filter"`
+				Ignore []*regexp.Regexp `ssm:"ignore"`
 			}{}),
 			want: []value{
-				{path: "Foo", value: aws.String("bar")},
+				{path: "Route", value: regexp.MustCompile(`^/users/(\d+)$`)},
+				{path: "Filter", value: *regexp.MustCompile("(?i)error")},
+				{path: "Ignore", value: []*regexp.Regexp{regexp.MustCompile("^health$"), regexp.MustCompile("^metrics$")}},
 			},
 		},
 		{
-			name: "Nested",
-			params: []ssm.Parameter{
-				stringParam("/root", "foo"),
-				stringParam("/db/user", "bar"),
-				stringParam("/db/password", "baz"),
-				stringParam("/ext/a", "qux"),
+			name: "ErrRegexp",
+			params: []types.Parameter{
+				stringParam("/route", "^/users/(\\d+$"),
 			},
 			config: reflect.TypeOf(struct {
-				Root     string `ssm:"root"`
-				Database struct {
-					User string `ssm:"user"`
-					Pass string `ssm:"password"`
-				} `ssm:"db"`
-				External *struct {
-					A string `ssm:"a"`
-				} `ssm:"ext"`
+				Route *regexp.Regexp `ssm:"route"`
 			}{}),
-			want: []value{
-				{path: "Root", value: "foo"},
-				{path: "Database.User", value: "bar"},
-				{path: "Database.Pass", value: "baz"},
-				{path: "External.A", value: "qux"},
-			},
+			wantErr: true,
 		},
 		{
-			name: "IngoreUnexported",
-			params: []ssm.Parameter{
-				stringParam("/foo", "foo"),
+			name:    "OptionWithParseLocation",
+			options: []Option{WithParseLocation()},
+			params: []types.Parameter{
+				stringParam("/tz", "Europe/Helsinki"),
+				stringParam("/utc", "UTC"),
+				stringListParam("/zones", "America/New_York,Asia/Tokyo"),
 			},
 			config: reflect.TypeOf(struct {
-				Foo string `ssm:"foo"`
-				bar string // no ssm tag
+				TZ    *time.Location   `ssm:"tz"`
+				UTC   *time.Location   `ssm:"utc"`
+				Zones []*time.Location `ssm:"zones"`
 			}{}),
 			want: []value{
-				{path: "Foo", value: "foo"},
+				{path: "TZ", value: mustLoadLocation("Europe/Helsinki")},
+				{path: "UTC", value: time.UTC},
+				{path: "Zones", value: []*time.Location{mustLoadLocation("America/New_York"), mustLoadLocation("Asia/Tokyo")}},
 			},
 		},
 		{
-			name:    "NotFound",
-			options: []Option{WithPrefix("prod")},
-			params: []ssm.Parameter{
-				stringParam("/dev/foo", "foo"),
+			name:    "ErrOptionWithParseLocation",
+			options: []Option{WithParseLocation()},
+			params: []types.Parameter{
+				stringParam("/tz", "Europe/Nowhere"),
 			},
 			config: reflect.TypeOf(struct {
-				Foo string `ssm:"foo"`
+				TZ *time.Location `ssm:"tz"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name: "ErrConvertStringToSlice",
-			params: []ssm.Parameter{
-				stringParam("/names", "alice"),
+			name:    "ErrOptionWithParseLocationLocal",
+			options: []Option{WithParseLocation()},
+			params: []types.Parameter{
+				stringParam("/tz", "Local"),
 			},
 			config: reflect.TypeOf(struct {
-				Names []string `ssm:"names"`
+				TZ *time.Location `ssm:"tz"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name: "ErrUnexportedWithTag",
-			params: []ssm.Parameter{
-				stringParam("/foo", "foo"),
+			name:    "ErrOptionWithParseBool",
+			options: []Option{WithParseBool()},
+			params: []types.Parameter{
+				stringParam("/a", "maybe"),
 			},
 			config: reflect.TypeOf(struct {
-				value string `ssm:"foo"` // nolint: unused
+				A bool `ssm:"a"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name: "ErrUnexportedNested",
-			params: []ssm.Parameter{
-				stringParam("/foo/bar", "foo"),
+			name: "ErrBoolWithoutParseBool",
+			params: []types.Parameter{
+				stringParam("/a", "true"),
 			},
 			config: reflect.TypeOf(struct {
-				Foo struct {
-					bar string `ssm:"bar"`
-				} `ssm:"foo"`
+				A bool `ssm:"a"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name: "ErrNotSupportedInt",
-			params: []ssm.Parameter{
-				stringParam("/number", "123"),
+			name:    "OptionWithParseNumber_Uint",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/a", "1"),
+				stringParam("/b", "255"),
+				stringParam("/c", "65535"),
+				stringParam("/d", "4294967295"),
+				stringParam("/e", "18446744073709551615"),
+				stringListParam("/ports", "80,443"),
 			},
 			config: reflect.TypeOf(struct {
-				Number int `ssm:"number"` // Not allowed without WithParseNumber
+				Uint   uint     `ssm:"a"`
+				Uint8  uint8    `ssm:"b"`
+				Uint16 uint16   `ssm:"c"`
+				Uint32 uint32   `ssm:"d"`
+				Uint64 uint64   `ssm:"e"`
+				Ports  []uint16 `ssm:"ports"`
 			}{}),
-			wantErr: true,
+			want: []value{
+				{path: "Uint", value: uint(1)},
+				{path: "Uint8", value: uint8(255)},
+				{path: "Uint16", value: uint16(65535)},
+				{path: "Uint32", value: uint32(4294967295)},
+				{path: "Uint64", value: uint64(18446744073709551615)},
+				{path: "Ports", value: []uint16{80, 443}},
+			},
 		},
 		{
-			name: "ErrStringListToString",
-			params: []ssm.Parameter{
-				stringListParam("/names", "alice,bob"),
+			name:    "ErrOptionWithParseNumber_UintOverflow",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/port", "65536"),
 			},
 			config: reflect.TypeOf(struct {
-				Names string `ssm:"names"` // Not allowed to set list
+				Port uint16 `ssm:"port"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name:    "ErrParseInt",
+			name:    "OptionWithParseNumber_IntSizes",
 			options: []Option{WithParseNumber()},
-			params: []ssm.Parameter{
-				stringParam("/name", "alice"),
+			params: []types.Parameter{
+				stringParam("/a", "-128"),
+				stringParam("/b", "32767"),
+				stringParam("/c", "-2147483648"),
 			},
 			config: reflect.TypeOf(struct {
-				Name int `ssm:"name"` // Cannot parse "alice" as int
+				Int8  int8  `ssm:"a"`
+				Int16 int16 `ssm:"b"`
+				Int32 int32 `ssm:"c"`
+			}{}),
+			want: []value{
+				{path: "Int8", value: int8(-128)},
+				{path: "Int16", value: int16(32767)},
+				{path: "Int32", value: int32(-2147483648)},
+			},
+		},
+		{
+			name:    "ErrOptionWithParseNumber_IntOverflow",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/level", "128"),
+			},
+			config: reflect.TypeOf(struct {
+				Level int8 `ssm:"level"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name:    "ErrParseFloat",
+			name:    "ErrOptionWithParseNumber_UintNegative",
 			options: []Option{WithParseNumber()},
-			params: []ssm.Parameter{
-				stringParam("/name", "alice"),
+			params: []types.Parameter{
+				stringParam("/size", "-1"),
 			},
 			config: reflect.TypeOf(struct {
-				Name float64 `ssm:"name"` // Cannot parse "alice" as float
+				Size uint `ssm:"size"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name: "ErrParseIntSlice",
-			params: []ssm.Parameter{
-				stringListParam("/names", "alice,bob"),
+			name:    "OptionWithParseNumber_Slice",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringListParam("/ints", "1,2,3"),
+				stringListParam("/floats", "1.23,4.56,7.89"),
 			},
 			config: reflect.TypeOf(struct {
-				Names []int `ssm:"names"` // Cannot parse "alice" as int
+				Ints   []int     `ssm:"ints"`
+				Floats []float64 `ssm:"floats"`
+			}{}),
+			want: []value{
+				{path: "Ints", value: []int{1, 2, 3}},
+				{path: "Floats", value: []float64{1.23, 4.56, 7.89}},
+			},
+		},
+		{
+			name: "OptionConverter",
+			options: []Option{WithConverter(reflect.TypeOf(point{}), func(v string) (interface{}, error) {
+				var p point
+				_, err := fmt.Sscanf(v, "%d:%d", &p.X, &p.Y)
+				return p, err
+			})},
+			params: []types.Parameter{
+				stringParam("/point", "1:2"),
+				stringListParam("/points", "3:4,5:6"),
+			},
+			config: reflect.TypeOf(struct {
+				Point  point   `ssm:"point"`
+				Points []point `ssm:"points"`
+			}{}),
+			want: []value{
+				{path: "Point", value: point{X: 1, Y: 2}},
+				{path: "Points", value: []point{{X: 3, Y: 4}, {X: 5, Y: 6}}},
+			},
+		},
+		{
+			name: "OptionConverterErr",
+			options: []Option{WithConverter(reflect.TypeOf(point{}), func(v string) (interface{}, error) {
+				return nil, fmt.Errorf("invalid point")
+			})},
+			params: []types.Parameter{
+				stringParam("/point", "1:2"),
+			},
+			config: reflect.TypeOf(struct {
+				Point point `ssm:"point"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name: "ErrEncryptedSlice",
-			params: []ssm.Parameter{
-				secureStringParam("/names", "alice"),
+			name: "OptionConverterErrType",
+			options: []Option{WithConverter(reflect.TypeOf(point{}), func(v string) (interface{}, error) {
+				return v, nil
+			})},
+			params: []types.Parameter{
+				stringParam("/point", "1:2"),
 			},
 			config: reflect.TypeOf(struct {
-				Names []string `ssm:"names"`
+				Point point `ssm:"point"`
 			}{}),
 			wantErr: true,
 		},
 		{
-			name: "ErrUnsupported",
-			params: []ssm.Parameter{
+			name: "SetPointer",
+			params: []types.Parameter{
 				stringParam("/foo", "bar"),
 			},
 			config: reflect.TypeOf(struct {
-				Chan chan string `ssm:"foo"`
+				Foo *string `ssm:"foo"`
+			}{}),
+			want: []value{
+				{path: "Foo", value: aws.String("bar")},
+			},
+		},
+		{
+			name: "SqlNull",
+			params: []types.Parameter{
+				stringParam("/str", "foo"),
+				stringParam("/empty", ""),
+				stringParam("/int", "123"),
+				stringParam("/bool", "true"),
+			},
+			config: reflect.TypeOf(struct {
+				Str   sql.NullString `ssm:"str"`
+				Empty sql.NullString `ssm:"empty"`
+				Int   sql.NullInt64  `ssm:"int"`
+				Bool  sql.NullBool   `ssm:"bool"`
+			}{}),
+			want: []value{
+				{path: "Str", value: sql.NullString{String: "foo", Valid: true}},
+				{path: "Empty", value: sql.NullString{String: "", Valid: true}},
+				{path: "Int", value: sql.NullInt64{Int64: 123, Valid: true}},
+				{path: "Bool", value: sql.NullBool{Bool: true, Valid: true}},
+			},
+		},
+		{
+			name: "SqlNull_BoolValues",
+			params: []types.Parameter{
+				stringParam("/yes", "yes"),
+				stringParam("/no", "NO"),
+				stringParam("/one", "1"),
+			},
+			config: reflect.TypeOf(struct {
+				Yes sql.NullBool `ssm:"yes"`
+				No  sql.NullBool `ssm:"no"`
+				One sql.NullBool `ssm:"one"`
+			}{}),
+			want: []value{
+				{path: "Yes", value: sql.NullBool{Bool: true, Valid: true}},
+				{path: "No", value: sql.NullBool{Bool: false, Valid: true}},
+				{path: "One", value: sql.NullBool{Bool: true, Valid: true}},
+			},
+		},
+		{
+			name: "SqlNull_Sized",
+			params: []types.Parameter{
+				stringParam("/int32", "-123"),
+				stringParam("/int16", "456"),
+				stringParam("/byte", "255"),
+				stringParam("/float", "1.5"),
+				stringParam("/time", "2024-03-01T12:00:00Z"),
+			},
+			config: reflect.TypeOf(struct {
+				Int32 sql.NullInt32   `ssm:"int32"`
+				Int16 sql.NullInt16   `ssm:"int16"`
+				Byte  sql.NullByte    `ssm:"byte"`
+				Float sql.NullFloat64 `ssm:"float"`
+				Time  sql.NullTime    `ssm:"time"`
+				Unset sql.NullInt32   `ssm:"unset,optional"`
+			}{}),
+			want: []value{
+				{path: "Int32", value: sql.NullInt32{Int32: -123, Valid: true}},
+				{path: "Int16", value: sql.NullInt16{Int16: 456, Valid: true}},
+				{path: "Byte", value: sql.NullByte{Byte: 255, Valid: true}},
+				{path: "Float", value: sql.NullFloat64{Float64: 1.5, Valid: true}},
+				{path: "Time", value: sql.NullTime{Time: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), Valid: true}},
+				{path: "Unset", value: sql.NullInt32{}},
+			},
+		},
+		{
+			name: "SqlNull_Slice",
+			params: []types.Parameter{
+				stringListParam("/ints", "1,2"),
+			},
+			config: reflect.TypeOf(struct {
+				Ints []sql.NullInt64 `ssm:"ints"`
+			}{}),
+			want: []value{
+				{path: "Ints", value: []sql.NullInt64{{Int64: 1, Valid: true}, {Int64: 2, Valid: true}}},
+			},
+		},
+		{
+			name: "BinaryUnmarshaler",
+			params: []types.Parameter{
+				secureStringParam("/key", "AQID"),
+				stringListParam("/keys", "AQ==,Ag=="),
+			},
+			config: reflect.TypeOf(struct {
+				Key  blob   `ssm:"key"`
+				Keys []blob `ssm:"keys"`
+			}{}),
+			want: []value{
+				{path: "Key", value: blob{Data: []byte{1, 2, 3}}},
+				{path: "Keys", value: []blob{{Data: []byte{1}}, {Data: []byte{2}}}},
+			},
+		},
+		{
+			name: "BinaryUnmarshalerEncodings",
+			params: []types.Parameter{
+				stringParam("/url", "-_-_"),
+				stringParam("/raw", "AQ"),
+				stringParam("/wrapped", "AQID\nBAU=\n"),
+			},
+			config: reflect.TypeOf(struct {
+				URL     blob `ssm:"url"`
+				Raw     blob `ssm:"raw"`
+				Wrapped blob `ssm:"wrapped"`
+			}{}),
+			want: []value{
+				{path: "URL", value: blob{Data: []byte{0xfb, 0xff, 0xbf}}},
+				{path: "Raw", value: blob{Data: []byte{1}}},
+				{path: "Wrapped", value: blob{Data: []byte{1, 2, 3, 4, 5}}},
+			},
+		},
+		{
+			name: "TextUnmarshaler",
+			params: []types.Parameter{
+				stringParam("/level", "WARN"),
+				stringListParam("/levels", "debug,error"),
+				stringParam("/pair", "a=b"),
+				stringParam("/date", "2020-01-02T15:04:05Z"),
+			},
+			config: reflect.TypeOf(struct {
+				Level  textLevel   `ssm:"level"`
+				Levels []textLevel `ssm:"levels"`
+				Pair   *textPair   `ssm:"pair"`
+				Date   time.Time   `ssm:"date"`
+			}{}),
+			want: []value{
+				{path: "Level", value: textLevel("warn")},
+				{path: "Levels", value: []textLevel{"debug", "error"}},
+				{path: "Pair", value: &textPair{Key: "a", Value: "b"}},
+				{path: "Date", value: time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)},
+			},
+		},
+		{
+			name: "ErrTextUnmarshaler",
+			params: []types.Parameter{
+				stringParam("/pair", "ab"),
+			},
+			config: reflect.TypeOf(struct {
+				Pair textPair `ssm:"pair"`
 			}{}),
 			wantErr: true,
 		},
-	}
+		{
+			name: "ErrTextUnmarshalerStringList",
+			params: []types.Parameter{
+				stringListParam("/pair", "a=b,c=d"),
+			},
+			config: reflect.TypeOf(struct {
+				Pair textPair `ssm:"pair"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "JSONUnmarshaler",
+			params: []types.Parameter{
+				stringParam("/color", "green"),
+				stringListParam("/colors", "red,green"),
+				stringParam("/quoted", `"red"`),
+				stringParam("/range", `{"min":1,"max":5}`),
+			},
+			config: reflect.TypeOf(struct {
+				Color  jsonColor   `ssm:"color"`
+				Colors []jsonColor `ssm:"colors"`
+				Quoted *jsonColor  `ssm:"quoted"`
+				Range  jsonRange   `ssm:"range"`
+			}{}),
+			want: []value{
+				{path: "Color", value: jsonColor(2)},
+				{path: "Colors", value: []jsonColor{1, 2}},
+				{path: "Quoted", value: func() *jsonColor { c := jsonColor(1); return &c }()},
+				{path: "Range", value: jsonRange{Min: 1, Max: 5}},
+			},
+		},
+		{
+			name: "ErrJSONUnmarshaler",
+			params: []types.Parameter{
+				stringParam("/color", "blue"),
+			},
+			config: reflect.TypeOf(struct {
+				Color jsonColor `ssm:"color"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "IP",
+			params: []types.Parameter{
+				stringParam("/ip", "10.0.0.1"),
+				stringParam("/cidr", "10.0.0.0/16"),
+				stringParam("/addr", "2001:db8::1"),
+				stringParam("/prefix", "192.168.0.0/24"),
+				stringListParam("/allow", "10.0.0.0/8,172.16.0.0/12"),
+				stringListParam("/peers", "10.0.0.2,10.0.0.3"),
+				stringListParam("/ranges", "10.0.0.0/8,fd00::/8"),
+			},
+			config: reflect.TypeOf(struct {
+				IP     net.IP         `ssm:"ip"`
+				CIDR   *net.IPNet     `ssm:"cidr"`
+				Addr   netip.Addr     `ssm:"addr"`
+				Prefix netip.Prefix   `ssm:"prefix"`
+				Allow  []net.IPNet    `ssm:"allow"`
+				Peers  []netip.Addr   `ssm:"peers"`
+				Ranges []netip.Prefix `ssm:"ranges"`
+			}{}),
+			want: []value{
+				{path: "IP", value: net.ParseIP("10.0.0.1")},
+				{path: "CIDR", value: &net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(16, 32)}},
+				{path: "Addr", value: netip.MustParseAddr("2001:db8::1")},
+				{path: "Prefix", value: netip.MustParsePrefix("192.168.0.0/24")},
+				{path: "Allow", value: []net.IPNet{
+					{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)},
+					{IP: net.IP{172, 16, 0, 0}, Mask: net.CIDRMask(12, 32)},
+				}},
+				{path: "Peers", value: []netip.Addr{netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.3")}},
+				{path: "Ranges", value: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")}},
+			},
+		},
+		{
+			name: "ErrIPInvalid",
+			params: []types.Parameter{
+				stringParam("/ip", "10.0.0.256"),
+			},
+			config: reflect.TypeOf(struct {
+				IP net.IP `ssm:"ip"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrIPNetInvalid",
+			params: []types.Parameter{
+				stringParam("/cidr", "10.0.0.0"),
+			},
+			config: reflect.TypeOf(struct {
+				CIDR net.IPNet `ssm:"cidr"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "BigNumbers",
+			params: []types.Parameter{
+				stringParam("/supply", "1000000000000000000000000000"),
+				stringParam("/price", "123456789.000000000000000001"),
+				stringParam("/ratio", "1/3"),
+				stringListParam("/amounts", "12345678901234567890,-98765432109876543210"),
+			},
+			config: reflect.TypeOf(struct {
+				Supply  *big.Int   `ssm:"supply"`
+				Price   big.Float  `ssm:"price"`
+				Ratio   *big.Rat   `ssm:"ratio"`
+				Amounts []*big.Int `ssm:"amounts"`
+			}{}),
+			want: []value{
+				{path: "Supply", value: bigInt("1000000000000000000000000000")},
+				{path: "Price", value: *bigFloat("123456789.000000000000000001")},
+				{path: "Ratio", value: big.NewRat(1, 3)},
+				{path: "Amounts", value: []*big.Int{bigInt("12345678901234567890"), bigInt("-98765432109876543210")}},
+			},
+		},
+		{
+			name: "ErrBigIntInvalid",
+			params: []types.Parameter{
+				stringParam("/supply", "1e18"),
+			},
+			config: reflect.TypeOf(struct {
+				Supply *big.Int `ssm:"supply"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrBigFloatInvalid",
+			params: []types.Parameter{
+				stringParam("/price", "1.0.0"),
+			},
+			config: reflect.TypeOf(struct {
+				Price *big.Float `ssm:"price"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "URL",
+			params: []types.Parameter{
+				stringParam("/endpoint", "https://api.example.com:8443/v1?x=1"),
+				stringParam("/proxy", "http://proxy.local"),
+				stringListParam("/mirrors", "https://a.example.com,https://b.example.com"),
+			},
+			config: reflect.TypeOf(struct {
+				Endpoint url.URL    `ssm:"endpoint"`
+				Proxy    *url.URL   `ssm:"proxy"`
+				Mirrors  []*url.URL `ssm:"mirrors"`
+			}{}),
+			want: []value{
+				{path: "Endpoint", value: url.URL{Scheme: "https", Host: "api.example.com:8443", Path: "/v1", RawQuery: "x=1"}},
+				{path: "Proxy", value: &url.URL{Scheme: "http", Host: "proxy.local"}},
+				{path: "Mirrors", value: []*url.URL{{Scheme: "https", Host: "a.example.com"}, {Scheme: "https", Host: "b.example.com"}}},
+			},
+		},
+		{
+			name: "ErrURLMissingScheme",
+			params: []types.Parameter{
+				stringParam("/endpoint", "api.example.com"),
+			},
+			config: reflect.TypeOf(struct {
+				Endpoint url.URL `ssm:"endpoint"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrURLInvalid",
+			params: []types.Parameter{
+				stringParam("/endpoint", "https://exa mple.com/%zz"),
+			},
+			config: reflect.TypeOf(struct {
+				Endpoint *url.URL `ssm:"endpoint"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "Nested",
+			params: []types.Parameter{
+				stringParam("/root", "foo"),
+				stringParam("/db/user", "bar"),
+				stringParam("/db/password", "baz"),
+				stringParam("/ext/a", "qux"),
+			},
+			config: reflect.TypeOf(struct {
+				Root     string `ssm:"root"`
+				Database struct {
+					User string `ssm:"user"`
+					Pass string `ssm:"password"`
+				} `ssm:"db"`
+				External *struct {
+					A string `ssm:"a"`
+				} `ssm:"ext"`
+			}{}),
+			want: []value{
+				{path: "Root", value: "foo"},
+				{path: "Database.User", value: "bar"},
+				{path: "Database.Pass", value: "baz"},
+				{path: "External.A", value: "qux"},
+			},
+		},
+		{
+			name: "IngoreUnexported",
+			params: []types.Parameter{
+				stringParam("/foo", "foo"),
+			},
+			config: reflect.TypeOf(struct {
+				Foo string `ssm:"foo"`
+				bar string // no ssm tag
+			}{}),
+			want: []value{
+				{path: "Foo", value: "foo"},
+			},
+		},
+		{
+			name:    "NotFound",
+			options: []Option{WithPrefix("prod")},
+			params: []types.Parameter{
+				stringParam("/dev/foo", "foo"),
+			},
+			config: reflect.TypeOf(struct {
+				Foo string `ssm:"foo"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrConvertStringToSlice",
+			params: []types.Parameter{
+				stringParam("/names", "alice"),
+			},
+			config: reflect.TypeOf(struct {
+				Names []string `ssm:"names"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrUnexportedWithTag",
+			params: []types.Parameter{
+				stringParam("/foo", "foo"),
+			},
+			config: reflect.TypeOf(struct {
+				value string `ssm:"foo"` // nolint: unused
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrUnexportedNested",
+			params: []types.Parameter{
+				stringParam("/foo/bar", "foo"),
+			},
+			config: reflect.TypeOf(struct {
+				Foo struct {
+					bar string `ssm:"bar"`
+				} `ssm:"foo"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrEmptyName",
+			params: []types.Parameter{
+				stringParam("/foo", "foo"),
+			},
+			config: reflect.TypeOf(struct {
+				Foo string `ssm:",unknown"` // Requires WithSplitWords
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrNotSupportedInt",
+			params: []types.Parameter{
+				stringParam("/number", "123"),
+			},
+			config: reflect.TypeOf(struct {
+				Number int `ssm:"number"` // Not allowed without WithParseNumber
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrStringListToString",
+			params: []types.Parameter{
+				stringListParam("/names", "alice,bob"),
+			},
+			config: reflect.TypeOf(struct {
+				Names string `ssm:"names"` // Not allowed to set list
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "ErrParseInt",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/name", "alice"),
+			},
+			config: reflect.TypeOf(struct {
+				Name int `ssm:"name"` // Cannot parse "alice" as int
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "ErrParseFloat",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/name", "alice"),
+			},
+			config: reflect.TypeOf(struct {
+				Name float64 `ssm:"name"` // Cannot parse "alice" as float
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrParseIntSlice",
+			params: []types.Parameter{
+				stringListParam("/names", "alice,bob"),
+			},
+			config: reflect.TypeOf(struct {
+				Names []int `ssm:"names"` // Cannot parse "alice" as int
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrEncryptedSlice",
+			params: []types.Parameter{
+				secureStringParam("/names", "alice"),
+			},
+			config: reflect.TypeOf(struct {
+				Names []string `ssm:"names"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrSqlNullInt",
+			params: []types.Parameter{
+				stringParam("/int", "foo"),
+			},
+			config: reflect.TypeOf(struct {
+				Int sql.NullInt64 `ssm:"int"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrSqlNullBool",
+			params: []types.Parameter{
+				stringParam("/bool", "foo"),
+			},
+			config: reflect.TypeOf(struct {
+				Bool sql.NullBool `ssm:"bool"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrSqlNullInt16Overflow",
+			params: []types.Parameter{
+				stringParam("/int", "40000"),
+			},
+			config: reflect.TypeOf(struct {
+				Int sql.NullInt16 `ssm:"int"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrSqlNullByteNegative",
+			params: []types.Parameter{
+				stringParam("/byte", "-1"),
+			},
+			config: reflect.TypeOf(struct {
+				Byte sql.NullByte `ssm:"byte"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrSqlNullTime",
+			params: []types.Parameter{
+				stringParam("/time", "yesterday"),
+			},
+			config: reflect.TypeOf(struct {
+				Time sql.NullTime `ssm:"time"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrSqlNullStringList",
+			params: []types.Parameter{
+				stringListParam("/names", "alice,bob"),
+			},
+			config: reflect.TypeOf(struct {
+				Names sql.NullString `ssm:"names"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "SliceOfPointers",
+			params: []types.Parameter{
+				stringListParam("/names", "alice,bob"),
+			},
+			config: reflect.TypeOf(struct {
+				Names []*string `ssm:"names"`
+			}{}),
+			want: []value{
+				{path: "Names", value: []*string{aws.String("alice"), aws.String("bob")}},
+			},
+		},
+		{
+			name:    "SliceOfIntPointers",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringListParam("/ports", "80,443"),
+			},
+			config: reflect.TypeOf(struct {
+				Ports []*int `ssm:"ports"`
+			}{}),
+			want: []value{
+				{path: "Ports", value: []*int{aws.Int(80), aws.Int(443)}},
+			},
+		},
+		{
+			name:    "PointerToSlice",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringListParam("/names", "alice,bob"),
+				stringListParam("/ports", "80,443"),
+			},
+			config: reflect.TypeOf(struct {
+				Names *[]string `ssm:"names"`
+				Ports *[]int    `ssm:"ports"`
+			}{}),
+			want: []value{
+				{path: "Names", value: &[]string{"alice", "bob"}},
+				{path: "Ports", value: &[]int{80, 443}},
+			},
+		},
+		{
+			name:    "ErrSliceOfIntPointers",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringListParam("/ports", "80,http"),
+			},
+			config: reflect.TypeOf(struct {
+				Ports []*int `ssm:"ports"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "Optional",
+			params: []types.Parameter{
+				stringParam("/foo", "bar"),
+			},
+			config: reflect.TypeOf(struct {
+				Foo     string  `ssm:"foo,optional"`
+				Missing string  `ssm:"missing,optional"`
+				Pointer *string `ssm:"pointer,optional"`
+			}{}),
+			want: []value{
+				{path: "Foo", value: "bar"},
+				{path: "Missing", value: ""},
+				{path: "Pointer", value: (*string)(nil)},
+			},
+		},
+		{
+			name: "ErrOptionalShared",
+			params: []types.Parameter{
+				stringParam("/foo", "bar"),
+			},
+			config: reflect.TypeOf(struct {
+				A string `ssm:"missing,optional"`
+				B string `ssm:"missing"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "Default",
+			options: []Option{WithParseDuration(), WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/host", "db.example.com"),
+			},
+			config: reflect.TypeOf(struct {
+				Host    string        `ssm:"host,default=localhost"`
+				Port    int           `ssm:"port,default=5432"`
+				Timeout time.Duration `ssm:"timeout,default=5s"`
+				Hosts   []string      `ssm:"hosts,default='a,b'"`
+				User    *string       `ssm:"user,default=admin"`
+			}{}),
+			want: []value{
+				{path: "Host", value: "db.example.com"},
+				{path: "Port", value: 5432},
+				{path: "Timeout", value: 5 * time.Second},
+				{path: "Hosts", value: []string{"a", "b"}},
+				{path: "User", value: aws.String("admin")},
+			},
+		},
+		{
+			name:    "ErrDefault",
+			options: []Option{WithParseNumber()},
+			config: reflect.TypeOf(struct {
+				Port int `ssm:"port,default=http"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "MapOfSlices",
+			params: []types.Parameter{
+				stringListParam("/endpoints/eu-west-1", "a.example.com,b.example.com"),
+				stringListParam("/endpoints/us-east-1", "c.example.com"),
+				stringListParam("/endpoints/nested/ignored", "d.example.com"),
+				stringParam("/other", "x"),
+			},
+			config: reflect.TypeOf(struct {
+				Endpoints map[string][]string `ssm:"endpoints"`
+				Other     string              `ssm:"other"`
+			}{}),
+			want: []value{
+				{path: "Endpoints", value: map[string][]string{
+					"eu-west-1": {"a.example.com", "b.example.com"},
+					"us-east-1": {"c.example.com"},
+				}},
+				{path: "Other", value: "x"},
+			},
+		},
+		{
+			name:    "Map",
+			options: []Option{WithPrefix("dev"), WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/dev/ports/http", "80"),
+				stringParam("/dev/ports/https", "443"),
+			},
+			config: reflect.TypeOf(struct {
+				Ports   map[string]int    `ssm:"ports"`
+				Missing map[string]string `ssm:"missing,optional"`
+			}{}),
+			want: []value{
+				{path: "Ports", value: map[string]int{"http": 80, "https": 443}},
+				{path: "Missing", value: map[string]string(nil)},
+			},
+		},
+		{
+			name: "ErrMapNotFound",
+			config: reflect.TypeOf(struct {
+				Endpoints map[string][]string `ssm:"endpoints"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrMapKey",
+			params: []types.Parameter{
+				stringParam("/ports/1", "80"),
+			},
+			config: reflect.TypeOf(struct {
+				Ports map[int]string `ssm:"ports"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrMapValue",
+			params: []types.Parameter{
+				stringParam("/endpoints/eu-west-1", "a.example.com"),
+			},
+			config: reflect.TypeOf(struct {
+				Endpoints map[string][]string `ssm:"endpoints"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "Empty",
+			params: []types.Parameter{
+				stringParam("/a", ""),
+				stringParam("/b", ""),
+			},
+			config: reflect.TypeOf(struct {
+				A *string        `ssm:"a"`
+				B sql.NullString `ssm:"b"`
+				C *string        `ssm:"c,optional"`
+			}{}),
+			want: []value{
+				{path: "A", value: aws.String("")},
+				{path: "B", value: sql.NullString{Valid: true}},
+				{path: "C", value: (*string)(nil)},
+			},
+		},
+		{
+			name:    "EmptyAsMissing",
+			options: []Option{WithEmptyAsMissing()},
+			params: []types.Parameter{
+				stringParam("/a", ""),
+				stringParam("/b", " "),
+				stringParam("/c", "c"),
+			},
+			config: reflect.TypeOf(struct {
+				A *string `ssm:"a,optional"`
+				B string  `ssm:"b,default=b"`
+				C string  `ssm:"c"`
+			}{}),
+			want: []value{
+				{path: "A", value: (*string)(nil)},
+				{path: "B", value: "b"},
+				{path: "C", value: "c"},
+			},
+		},
+		{
+			name:    "ErrEmptyAsMissing",
+			options: []Option{WithEmptyAsMissing()},
+			params: []types.Parameter{
+				stringParam("/a", ""),
+			},
+			config: reflect.TypeOf(struct {
+				A string `ssm:"a"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "AbsoluteName",
+			options: []Option{WithPrefix("dev/app")},
+			params: []types.Parameter{
+				stringParam("/dev/app/db/host", "db.example.com"),
+				stringParam("/shared/kms_key_id", "key"),
+				stringParam("/shared/db/user", "alice"),
+			},
+			config: reflect.TypeOf(struct {
+				KMSKeyID string `ssm:"/shared/kms_key_id"`
+				DB       struct {
+					Host string `ssm:"host"`
+					User string `ssm:"/shared/db/user"`
+				} `ssm:"db"`
+				Shared struct {
+					User string `ssm:"user"`
+				} `ssm:"/shared/db"`
+			}{}),
+			want: []value{
+				{path: "KMSKeyID", value: "key"},
+				{path: "DB.Host", value: "db.example.com"},
+				{path: "DB.User", value: "alice"},
+				{path: "Shared.User", value: "alice"},
+			},
+		},
+		{
+			name:    "SharedByARN",
+			options: []Option{WithPrefix("dev")},
+			params: []types.Parameter{
+				func() types.Parameter {
+					p := stringParam("/shared/db", "db.example.com")
+					p.ARN = aws.String("arn:aws:ssm:eu-west-1:123456789012:parameter/shared/db")
+					return p
+				}(),
+			},
+			config: reflect.TypeOf(struct {
+				DB string `ssm:"arn:aws:ssm:eu-west-1:123456789012:parameter/shared/db"`
+			}{}),
+			want: []value{
+				{path: "DB", value: "db.example.com"},
+			},
+		},
+		{
+			name:    "Inline",
+			options: []Option{WithPrefix("dev")},
+			params: []types.Parameter{
+				stringParam("/dev/region", "eu-west-1"),
+				stringParam("/dev/db/host", "db.example.com"),
+				stringParam("/dev/db/region", "us-east-1"),
+			},
+			config: reflect.TypeOf(struct {
+				AWS struct {
+					Region string `ssm:"region"`
+				} `ssm:",inline"`
+				DB struct {
+					Host string `ssm:"host"`
+					AWS  *struct {
+						Region string `ssm:"region"`
+					} `ssm:",squash"`
+				} `ssm:"db"`
+			}{}),
+			want: []value{
+				{path: "AWS.Region", value: "eu-west-1"},
+				{path: "DB.Host", value: "db.example.com"},
+				{path: "DB.AWS.Region", value: "us-east-1"},
+			},
+		},
+		{
+			name: "ErrInlineNotStruct",
+			params: []types.Parameter{
+				stringParam("/foo", "bar"),
+			},
+			config: reflect.TypeOf(struct {
+				Foo string `ssm:"foo,inline"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "Embedded",
+			options: []Option{WithPrefix("dev")},
+			params: []types.Parameter{
+				stringParam("/dev/region", "eu-west-1"),
+				stringParam("/dev/user", "alice"),
+				stringParam("/dev/host", "db.example.com"),
+				stringParam("/dev/db/region", "us-east-1"),
+			},
+			config: reflect.TypeOf(struct {
+				EmbeddedBase
+				embeddedAuth
+				Host string `ssm:"host"`
+				DB   struct {
+					*EmbeddedBase
+				} `ssm:"db"`
+			}{}),
+			want: []value{
+				{path: "EmbeddedBase.Region", value: "eu-west-1"},
+				{path: "embeddedAuth.User", value: "alice"},
+				{path: "Host", value: "db.example.com"},
+				{path: "DB.EmbeddedBase.Region", value: "us-east-1"},
+			},
+		},
+		{
+			name: "EmbeddedTagged",
+			params: []types.Parameter{
+				stringParam("/base/region", "eu-west-1"),
+			},
+			config: reflect.TypeOf(struct {
+				EmbeddedBase `ssm:"base"`
+			}{}),
+			want: []value{
+				{path: "EmbeddedBase.Region", value: "eu-west-1"},
+			},
+		},
+		{
+			name: "ErrEmbeddedUnexportedPointer",
+			params: []types.Parameter{
+				stringParam("/user", "alice"),
+			},
+			config: reflect.TypeOf(struct {
+				*embeddedAuth
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "NoDecrypt",
+			params: []types.Parameter{
+				secureStringParam("/token", "secret"),
+				secureStringParam("/password", "hunter2"),
+			},
+			config: reflect.TypeOf(struct {
+				Token    string `ssm:"token,nodecrypt"`
+				Password string `ssm:"password"`
+			}{}),
+			want: []value{
+				{path: "Token", value: "<ENCRYPTED>"},
+				{path: "Password", value: "hunter2"},
+			},
+		},
+		{
+			name:    "OptionDecryptionFalse",
+			options: []Option{WithDecryption(false), WithPrefix("app"), WithPathFetch()},
+			params: []types.Parameter{
+				secureStringParam("/app/token", "secret"),
+				secureStringParam("/app/keys/a", "key"),
+				secureStringParam("/shared/password", "hunter2"),
+			},
+			config: reflect.TypeOf(struct {
+				Token    string            `ssm:"token"`
+				Keys     map[string]string `ssm:"keys"`
+				Password string            `ssm:"/shared/password"`
+			}{}),
+			want: []value{
+				{path: "Token", value: "<ENCRYPTED>"},
+				{path: "Keys", value: map[string]string{"a": "<ENCRYPTED>"}},
+				{path: "Password", value: "<ENCRYPTED>"},
+			},
+		},
+		{
+			name: "PinnedVersion",
+			params: []types.Parameter{
+				{Name: aws.String("/host"), Value: aws.String("old.example.com"), Type: types.ParameterTypeString, Version: 1},
+				{Name: aws.String("/host"), Value: aws.String("new.example.com"), Type: types.ParameterTypeString, Version: 2},
+				{Name: aws.String("/port"), Value: aws.String("5432"), Type: types.ParameterTypeString, Version: 1},
+				{Name: aws.String("/port"), Value: aws.String("6543"), Type: types.ParameterTypeString, Version: 2},
+			},
+			config: reflect.TypeOf(struct {
+				Host       string `ssm:"host,version=1"`
+				LatestHost string `ssm:"host"`
+				Port       string `ssm:"port:1"`
+			}{}),
+			want: []value{
+				{path: "Host", value: "old.example.com"},
+				{path: "LatestHost", value: "new.example.com"},
+				{path: "Port", value: "5432"},
+			},
+		},
+		{
+			name: "Label",
+			params: []types.Parameter{
+				{Name: aws.String("/host"), Value: aws.String("old.example.com"), Type: types.ParameterTypeString, Version: 1},
+				{Name: aws.String("/host"), Value: aws.String("new.example.com"), Type: types.ParameterTypeString, Version: 2},
+			},
+			selectors: map[string]int64{"/host:prod-approved": 1},
+			config: reflect.TypeOf(struct {
+				Host       string `ssm:"host,label=prod-approved"`
+				LatestHost string `ssm:"host"`
+			}{}),
+			want: []value{
+				{path: "Host", value: "old.example.com"},
+				{path: "LatestHost", value: "new.example.com"},
+			},
+		},
+		{
+			name:    "OptionLabel",
+			options: []Option{WithLabel("stable")},
+			params: []types.Parameter{
+				{Name: aws.String("/host"), Value: aws.String("old.example.com"), Type: types.ParameterTypeString, Version: 1},
+				{Name: aws.String("/host"), Value: aws.String("new.example.com"), Type: types.ParameterTypeString, Version: 2},
+				{Name: aws.String("/port"), Value: aws.String("5432"), Type: types.ParameterTypeString, Version: 1},
+				{Name: aws.String("/port"), Value: aws.String("6543"), Type: types.ParameterTypeString, Version: 2},
+				stringParam("/endpoints/a", "a.example.com"),
+			},
+			selectors: map[string]int64{"/host:stable": 1, "/port:canary": 2},
+			config: reflect.TypeOf(struct {
+				Host      string            `ssm:"host"`
+				Port      string            `ssm:"port,label=canary"`
+				Endpoints map[string]string `ssm:"endpoints"`
+			}{}),
+			want: []value{
+				{path: "Host", value: "old.example.com"},
+				{path: "Port", value: "6543"},
+				{path: "Endpoints", value: map[string]string{"a": "a.example.com"}},
+			},
+		},
+		{
+			name: "ErrVersionAndLabel",
+			params: []types.Parameter{
+				stringParam("/host", "db.example.com"),
+			},
+			config: reflect.TypeOf(struct {
+				Host string `ssm:"host,version=1,label=stable"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "PinnedVersionNotFound",
+			params: []types.Parameter{
+				stringParam("/host", "db.example.com"),
+			},
+			config: reflect.TypeOf(struct {
+				Host string `ssm:"host,version=5"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrInvalidVersion",
+			params: []types.Parameter{
+				stringParam("/host", "db.example.com"),
+			},
+			config: reflect.TypeOf(struct {
+				Host string `ssm:"host,version=latest"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "Separator",
+			params: []types.Parameter{
+				stringParam("/hosts", "a,b;c"),
+				secureStringParam("/ports", "80|443"),
+			},
+			config: reflect.TypeOf(struct {
+				Hosts   []string `ssm:"hosts,sep=;"`
+				Ports   []string `ssm:"ports,sep=|"`
+				Default []string `ssm:"default,sep=;,default='x,y;z'"`
+			}{}),
+			want: []value{
+				{path: "Hosts", value: []string{"a,b", "c"}},
+				{path: "Ports", value: []string{"80", "443"}},
+				{path: "Default", value: []string{"x,y", "z"}},
+			},
+		},
+		{
+			name: "ErrSeparatorStringList",
+			params: []types.Parameter{
+				stringListParam("/hosts", "a,b"),
+			},
+			config: reflect.TypeOf(struct {
+				Hosts []string `ssm:"hosts,sep=;"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrSeparatorNotSlice",
+			params: []types.Parameter{
+				stringParam("/host", "a;b"),
+			},
+			config: reflect.TypeOf(struct {
+				Host string `ssm:"host,sep=;"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "JSON",
+			params: []types.Parameter{
+				stringParam("/features", `{"beta":true,"limits":{"users":10}}`),
+				secureStringParam("/weights", `{"a":1,"b":2}`),
+				stringParam("/hosts", `["a,b","c"]`),
+			},
+			config: reflect.TypeOf(struct {
+				Features jsonFeatures      `ssm:"features,json"`
+				Weights  map[string]int    `ssm:"weights,json"`
+				Hosts    []string          `ssm:"hosts,json"`
+				Default  *jsonFeatures     `ssm:"default,json,default='{\"beta\":true}'"`
+				Missing  map[string]string `ssm:"missing,json,optional"`
+			}{}),
+			want: []value{
+				{path: "Features", value: jsonFeatures{Beta: true, Limits: map[string]int{"users": 10}}},
+				{path: "Weights", value: map[string]int{"a": 1, "b": 2}},
+				{path: "Hosts", value: []string{"a,b", "c"}},
+				{path: "Default", value: &jsonFeatures{Beta: true}},
+				{path: "Missing", value: map[string]string(nil)},
+			},
+		},
+		{
+			name: "ErrJSONInvalid",
+			params: []types.Parameter{
+				stringParam("/features", `{"beta":`),
+			},
+			config: reflect.TypeOf(struct {
+				Features jsonFeatures `ssm:"features,json"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrJSONStringList",
+			params: []types.Parameter{
+				stringListParam("/hosts", "a,b"),
+			},
+			config: reflect.TypeOf(struct {
+				Hosts []string `ssm:"hosts,json"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "KeyValuePairs",
+			options: []Option{WithParseDuration(), WithParseNumber()},
+			params: []types.Parameter{
+				stringListParam("/labels", "team=payments,url=https://a.example.com/?x=1"),
+				stringParam("/limits", "api=100;batch=10"),
+				stringParam("/timeouts", `{"read": "5s", "write": "10s"}`),
+				secureStringParam("/weights", `{"a": 1, "b": 2.5}`),
+				stringParam("/empty", ""),
+			},
+			config: reflect.TypeOf(struct {
+				Labels   map[string]string        `ssm:"labels,kv"`
+				Limits   *map[string]int          `ssm:"limits,kv,sep=;"`
+				Timeouts map[string]time.Duration `ssm:"timeouts,kv"`
+				Weights  map[string]float64       `ssm:"weights,kv"`
+				Empty    map[string]string        `ssm:"empty,kv"`
+				Default  map[string]int           `ssm:"default,kv,default='x=1,y=2'"`
+				Missing  map[string]string        `ssm:"missing,kv,optional"`
+			}{}),
+			want: []value{
+				{path: "Labels", value: map[string]string{"team": "payments", "url": "https://a.example.com/?x=1"}},
+				{path: "Limits", value: &map[string]int{"api": 100, "batch": 10}},
+				{path: "Timeouts", value: map[string]time.Duration{"read": 5 * time.Second, "write": 10 * time.Second}},
+				{path: "Weights", value: map[string]float64{"a": 1, "b": 2.5}},
+				{path: "Empty", value: map[string]string{}},
+				{path: "Default", value: map[string]int{"x": 1, "y": 2}},
+				{path: "Missing", value: map[string]string(nil)},
+			},
+		},
+		{
+			name: "ErrKeyValuePairsMissingValue",
+			params: []types.Parameter{
+				stringListParam("/labels", "team=payments,prod"),
+			},
+			config: reflect.TypeOf(struct {
+				Labels map[string]string `ssm:"labels,kv"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrKeyValuePairsDuplicate",
+			params: []types.Parameter{
+				stringListParam("/labels", "a=1,a=2"),
+			},
+			config: reflect.TypeOf(struct {
+				Labels map[string]string `ssm:"labels,kv"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "ErrKeyValuePairsConversion",
+			options: []Option{WithParseNumber()},
+			params: []types.Parameter{
+				stringParam("/limits", `{"api": "many"}`),
+			},
+			config: reflect.TypeOf(struct {
+				Limits map[string]int `ssm:"limits,kv"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrKeyValuePairsNotMap",
+			params: []types.Parameter{
+				stringParam("/labels", "a=1"),
+			},
+			config: reflect.TypeOf(struct {
+				Labels []string `ssm:"labels,kv"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "DataType",
+			params: []types.Parameter{
+				dataTypeParam("/ami", "ami-0123456789abcdef0", "aws:ec2:image"),
+				dataTypeParam("/old_ami", "ami-01234567", "aws:ec2:image"),
+				dataTypeParam("/text", "42", "text"),
+				dataTypeParam("/webhook", `{"url":"https://example.com"}`, "aws:ssm:integration"),
+			},
+			options: []Option{WithParseNumber()},
+			config: reflect.TypeOf(struct {
+				AMI     string            `ssm:"ami"`
+				OldAMI  *string           `ssm:"old_ami"`
+				Text    int               `ssm:"text"`
+				Webhook string            `ssm:"webhook"`
+				URL     map[string]string `ssm:"webhook,json"`
+			}{}),
+			want: []value{
+				{path: "AMI", value: "ami-0123456789abcdef0"},
+				{path: "OldAMI", value: aws.String("ami-01234567")},
+				{path: "Text", value: 42},
+				{path: "Webhook", value: `{"url":"https://example.com"}`},
+				{path: "URL", value: map[string]string{"url": "https://example.com"}},
+			},
+		},
+		{
+			name: "ErrDataTypeImageInt",
+			params: []types.Parameter{
+				dataTypeParam("/ami", "ami-01234567", "aws:ec2:image"),
+			},
+			options: []Option{WithParseNumber()},
+			config: reflect.TypeOf(struct {
+				AMI int `ssm:"ami"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrDataTypeImageInvalid",
+			params: []types.Parameter{
+				dataTypeParam("/ami", "ubuntu", "aws:ec2:image"),
+			},
+			config: reflect.TypeOf(struct {
+				AMI string `ssm:"ami"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name:    "RootAnchored",
+			options: []Option{WithPrefix("dev")},
+			params: []types.Parameter{
+				stringParam("/dev/region", "eu-west-1"),
+				stringParam("/dev/bucket", "assets"),
+				stringParam("/dev/service/host", "localhost"),
+				stringParam("/dev/shared/key", "abc"),
+			},
+			config: reflect.TypeOf(struct {
+				Service struct {
+					Region string `ssm:"~/region"`
+					Bucket string `ssm:"bucket,root"`
+					Host   string `ssm:"host"`
+					Shared struct {
+						Key string `ssm:"key"`
+					} `ssm:"~/shared"`
+				} `ssm:"service"`
+			}{}),
+			want: []value{
+				{path: "Service.Region", value: "eu-west-1"},
+				{path: "Service.Bucket", value: "assets"},
+				{path: "Service.Host", value: "localhost"},
+				{path: "Service.Shared.Key", value: "abc"},
+			},
+		},
+		{
+			name: "SharedParameter",
+			params: []types.Parameter{
+				stringParam("/aws/region", "eu-west-1"),
+			},
+			config: reflect.TypeOf(struct {
+				AWS struct {
+					Region string `ssm:"region"`
+				} `ssm:"aws"`
+				Region       string `ssm:"aws/region"`
+				BucketRegion string `ssm:"aws/region"`
+			}{}),
+			want: []value{
+				{path: "AWS.Region", value: "eu-west-1"},
+				{path: "Region", value: "eu-west-1"},
+				{path: "BucketRegion", value: "eu-west-1"},
+			},
+		},
+		{
+			name: "DeeplyNested",
+			params: []types.Parameter{
+				stringParam("/a/b/c/d", "1"),
+				stringParam("/a/b/c/e", "2"),
+			},
+			config: reflect.TypeOf(struct {
+				A struct {
+					B struct {
+						C struct {
+							D string `ssm:"d"`
+							E string `ssm:"e"`
+						} `ssm:"c"`
+					} `ssm:"b"`
+				} `ssm:"a"`
+			}{}),
+			want: []value{
+				{path: "A.B.C.D", value: "1"},
+				{path: "A.B.C.E", value: "2"},
+			},
+		},
+		{
+			name: "ErrBinaryBase64",
+			params: []types.Parameter{
+				stringParam("/key", "not base64"),
+			},
+			config: reflect.TypeOf(struct {
+				Key blob `ssm:"key"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrBinaryUnmarshal",
+			params: []types.Parameter{
+				stringParam("/key", ""),
+			},
+			config: reflect.TypeOf(struct {
+				Key blob `ssm:"key"`
+			}{}),
+			wantErr: true,
+		},
+		{
+			name: "ErrUnsupported",
+			params: []types.Parameter{
+				stringParam("/foo", "bar"),
+			},
+			config: reflect.TypeOf(struct {
+				Chan chan string `ssm:"foo"`
+			}{}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSSM{params: tt.params, selectors: tt.selectors}
+			ps, err := NewParamStore(
+				append(tt.options, WithClient(mock))...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			val := reflect.New(tt.config)
+			got := val.Interface()
+			err = ps.Read(context.Background(), got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() err = %v, want err = %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				t.Logf("Got expected error: %v", err)
+			}
+			check(t, val.Elem().Interface(), tt.want)
+		})
+	}
+}
+
+func TestParamStore_Read_existingPointer(t *testing.T) {
+	type db struct {
+		User string `ssm:"user"`
+		Pass string `ssm:"pass"`
+	}
+	name := "old"
+	database := &db{Pass: "secret"}
+	cfg := struct {
+		Name *string `ssm:"name"`
+		DB   *db     `ssm:"db"`
+	}{
+		Name: &name,
+		DB:   database,
+	}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/name", "new"),
+		stringParam("/db/user", "alice"),
+		stringParam("/db/pass", "bob"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != &name || name != "new" {
+		t.Errorf("Name = %q, want existing pointer to be set", *cfg.Name)
+	}
+	if cfg.DB != database || database.User != "alice" || database.Pass != "bob" {
+		t.Errorf("DB = %+v, want existing pointer to be set", cfg.DB)
+	}
+}
+
+func TestNewParamStore_awsConfig(t *testing.T) {
+	cfg := aws.Config{Region: "eu-west-1"}
+	ps, err := NewParamStore(WithAWSConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, ok := ps.cli.(*ssm.Client)
+	if !ok {
+		t.Fatalf("Client is %T, want *ssm.Client", ps.cli)
+	}
+	if got := cli.Options().Region; got != "eu-west-1" {
+		t.Errorf("Region = %q, want %q", got, "eu-west-1")
+	}
+}
+
+func TestNewParamStore_region(t *testing.T) {
+	cfg := aws.Config{Region: "eu-west-1"}
+	ps, err := NewParamStore(WithAWSConfig(cfg), WithRegion("us-east-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := ps.cli.(*ssm.Client)
+	if got := cli.Options().Region; got != "us-east-1" {
+		t.Errorf("Region = %q, want %q", got, "us-east-1")
+	}
+}
+
+func TestNewParamStore_endpoint(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if got := r.Header.Get("X-Amz-Target"); got != "AmazonSSM.GetParameter" {
+			t.Errorf("X-Amz-Target = %q, want AmazonSSM.GetParameter", got)
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		fmt.Fprint(w, `{"Parameter":{"Name":"/foo","Type":"String","Value":"bar"}}`)
+	}))
+	defer srv.Close()
+
+	cfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}
+	ps, err := NewParamStore(
+		WithAWSConfig(cfg),
+		WithEndpoint(srv.URL),
+		WithHTTPClient(srv.Client()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Foo string `ssm:"foo"`
+	}
+	if err := ps.Read(context.Background(), &got); err != nil {
+		t.Fatal(err)
+	}
+	check(t, got, []value{{path: "Foo", value: "bar"}})
+	if calls != 1 {
+		t.Errorf("Endpoint called %d times, want 1", calls)
+	}
+}
+
+func TestWithAPIOptions(t *testing.T) {
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Request-Source")
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		fmt.Fprint(w, `{"Parameter":{"Name":"/foo","Type":"String","Value":"bar"}}`)
+	}))
+	defer srv.Close()
+
+	setHeader := middleware.BuildMiddlewareFunc("SetHeader", func(
+		ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler,
+	) (middleware.BuildOutput, middleware.Metadata, error) {
+		if req, ok := in.Request.(*smithyhttp.Request); ok {
+			req.Header.Set("X-Request-Source", "test")
+		}
+		return next.HandleBuild(ctx, in)
+	})
+	cfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}
+	ps, err := NewParamStore(
+		WithAWSConfig(cfg),
+		WithEndpoint(srv.URL),
+		WithHTTPClient(srv.Client()),
+		WithAPIOptions(func(stack *middleware.Stack) error {
+			return stack.Build.Add(setHeader, middleware.After)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Foo string `ssm:"foo"`
+	}
+	if err := ps.Read(context.Background(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if header != "test" {
+		t.Errorf("X-Request-Source = %q, want %q", header, "test")
+	}
+}
+
+func TestWithAppName(t *testing.T) {
+	var userAgent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		fmt.Fprint(w, `{"Parameter":{"Name":"/foo","Type":"String","Value":"bar"}}`)
+	}))
+	defer srv.Close()
+
+	cfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}
+	ps, err := NewParamStore(
+		WithAWSConfig(cfg),
+		WithEndpoint(srv.URL),
+		WithHTTPClient(srv.Client()),
+		WithAppName("billing-api"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mock := &mockSSM{params: tt.params}
-			ps, err := NewParamStore(
-				append(tt.options, WithClient(mock))...,
-			)
-			if err != nil {
-				t.Fatal(err)
-			}
+	var got struct {
+		Foo string `ssm:"foo"`
+	}
+	if err := ps.Read(context.Background(), &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"akupila-ssm/devel", "app/billing-api"} {
+		if !strings.Contains(userAgent, want) {
+			t.Errorf("User-Agent = %q, want to contain %q", userAgent, want)
+		}
+	}
+}
 
-			val := reflect.New(tt.config)
-			got := val.Interface()
-			err = ps.Read(context.Background(), got)
-			if (err != nil) != tt.wantErr {
-				t.Fatalf("Read() err = %v, want err = %t", err, tt.wantErr)
-			}
-			if tt.wantErr {
-				t.Logf("Got expected error: %v", err)
+func TestParamStore_Read_converterContext(t *testing.T) {
+	type tenantKey struct{}
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/point", "1:2"),
+		stringParam("/slow", "3:4"),
+	}}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithConverterFunc(reflect.TypeOf(point{}), func(ctx context.Context, v string) (interface{}, error) {
+			if v == "3:4" {
+				// Resolving the value blocks until the read is canceled
+				<-ctx.Done()
+				return nil, ctx.Err()
 			}
-			check(t, val.Elem().Interface(), tt.want)
-		})
+			p := point{X: ctx.Value(tenantKey{}).(int)}
+			_, err := fmt.Sscanf(v, "%d:%d", &p.X, &p.Y)
+			return p, err
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Point point `ssm:"point"`
+	}
+	ctx := context.WithValue(context.Background(), tenantKey{}, 5)
+	if err := ps.Read(ctx, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "Point", value: point{X: 1, Y: 2}}})
+
+	var slow struct {
+		Slow point `ssm:"slow"`
+	}
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	err = ps.Read(ctx, &slow)
+	if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+		t.Errorf("Read() err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestParamStore_Read_location(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/tz", "UTC"),
+		stringParam("/local", "Europe/Helsinki"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithParseLocation())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		TZ    *time.Location `ssm:"tz"`
+		Local *time.Location `ssm:"local"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TZ != time.UTC {
+		t.Errorf("TZ = %p, want time.UTC (%p)", cfg.TZ, time.UTC)
+	}
+	if got := time.Date(2024, 1, 1, 12, 0, 0, 0, cfg.Local).Format("-07:00"); got != "+02:00" {
+		t.Errorf("Local offset = %s, want +02:00", got)
 	}
 }
 
@@ -459,28 +2327,258 @@ func TestParamStore_Read_ssmError(t *testing.T) {
 	}
 }
 
-func stringParam(name, value string) ssm.Parameter {
-	return ssm.Parameter{
+func TestParamStore_Read_descriptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{
+			name:    "WithDescriptions",
+			options: []Option{WithDescriptions()},
+			want:    `/timeout: time: missing unit in duration "30" (expected duration, e.g. 30s)`,
+		},
+		{
+			name: "WithoutDescriptions",
+			want: `/timeout: time: missing unit in duration "30"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := struct {
+				Timeout time.Duration `ssm:"timeout"`
+			}{}
+
+			mock := &mockSSM{
+				params: []types.Parameter{
+					stringParam("/timeout", "30"),
+				},
+				descriptions: map[string]string{
+					"/timeout": "expected duration, e.g. 30s",
+				},
+			}
+			ps, err := NewParamStore(
+				append(tt.options, WithClient(mock), WithParseDuration())...,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ps.Read(context.Background(), &cfg)
+			if err == nil {
+				t.Fatal("Want error")
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamStore_Read_descriptionsSelector(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			{Name: aws.String("/timeout"), Value: aws.String("30"), Type: types.ParameterTypeString, Version: 3},
+		},
+		descriptions: map[string]string{
+			"/timeout": "expected duration, e.g. 30s",
+		},
+	}
+	ps, err := NewParamStore(WithClient(mock), WithParseDuration(), WithDescriptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Timeout time.Duration `ssm:"timeout,version=3"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	want := `/timeout:3: time: missing unit in duration "30" (expected duration, e.g. 30s)`
+	if err == nil || err.Error() != want {
+		t.Errorf("Error = %v, want %q", err, want)
+	}
+}
+
+func TestParamStore_Read_logger(t *testing.T) {
+	cfg := struct {
+		User string `ssm:"user"`
+		Pass string `ssm:"pass"`
+	}{}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/user", "alice"),
+		secureStringParam("/pass", "secret"),
+	}}
+	mock.params[1].Version = 3
+	ps, err := NewParamStore(WithClient(mock), WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `level=DEBUG msg="read parameter" name=/pass version=3
+level=DEBUG msg="read parameter" name=/user version=0
+level=INFO msg="read parameters" count=2
+`
+	if diff := cmp.Diff(buf.String(), want); diff != "" {
+		t.Errorf("Log (-got +want)\n%s", diff)
+	}
+}
+
+func stringParam(name, value string) types.Parameter {
+	return types.Parameter{
 		Name:  aws.String(name),
 		Value: aws.String(value),
-		Type:  ssm.ParameterTypeString,
+		Type:  types.ParameterTypeString,
 	}
 }
 
-func stringListParam(name, value string) ssm.Parameter {
-	return ssm.Parameter{
+func dataTypeParam(name, value, dataType string) types.Parameter {
+	p := stringParam(name, value)
+	p.DataType = aws.String(dataType)
+	return p
+}
+
+func stringListParam(name, value string) types.Parameter {
+	return types.Parameter{
 		Name:  aws.String(name),
 		Value: aws.String(value),
-		Type:  ssm.ParameterTypeStringList,
+		Type:  types.ParameterTypeStringList,
 	}
 }
 
-func secureStringParam(name, value string) ssm.Parameter {
-	return ssm.Parameter{
+func secureStringParam(name, value string) types.Parameter {
+	return types.Parameter{
 		Name:  aws.String(name),
 		Value: aws.String(value),
-		Type:  ssm.ParameterTypeSecureString,
+		Type:  types.ParameterTypeSecureString,
+	}
+}
+
+// protoConfig resembles a struct generated by protoc-gen-go.
+type protoConfig struct {
+	state         struct{} // nolint: unused
+	sizeCache     int32    // nolint: unused
+	unknownFields []byte   // nolint: unused
+
+	DisplayName string         `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Database    *protoDatabase `protobuf:"bytes,2,opt,name=database,proto3" json:"database,omitempty"`
+	Tagged      string         `protobuf:"bytes,3,opt,name=other,proto3" ssm:"tagged"`
+	Kind        isKind         `protobuf_oneof:"kind"`
+}
+
+// EmbeddedBase is embedded in configs.
+type EmbeddedBase struct {
+	Region string `ssm:"region"`
+}
+
+// jsonFeatures is decoded from a JSON parameter.
+type jsonFeatures struct {
+	Beta   bool           `json:"beta"`
+	Limits map[string]int `json:"limits"`
+}
+
+type embeddedAuth struct {
+	User string `ssm:"user"`
+}
+
+type protoDatabase struct {
+	HostName string `protobuf:"bytes,1,opt,name=host_name,json=hostName,proto3" json:"host_name,omitempty"`
+}
+
+type isKind interface {
+	isKind()
+}
+
+type point struct {
+	X, Y int
+}
+
+// blob implements encoding.BinaryUnmarshaler.
+type blob struct {
+	Data []byte
+}
+
+// jsonColor is an enum read with UnmarshalJSON from a quoted name.
+type jsonColor int
+
+func (c *jsonColor) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	switch name {
+	case "red":
+		*c = 1
+	case "green":
+		*c = 2
+	default:
+		return fmt.Errorf("unknown color %q", name)
+	}
+	return nil
+}
+
+// jsonRange is a struct read with UnmarshalJSON, instead of as nested values.
+type jsonRange struct {
+	Min, Max int
+}
+
+func (r *jsonRange) UnmarshalJSON(data []byte) error {
+	var v struct{ Min, Max int }
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.Min, r.Max = v.Min, v.Max
+	return nil
+}
+
+// textLevel is a string type read with UnmarshalText.
+type textLevel string
+
+func (l *textLevel) UnmarshalText(text []byte) error {
+	*l = textLevel(strings.ToLower(string(text)))
+	return nil
+}
+
+// textPair implements both encoding.TextUnmarshaler and
+// encoding.BinaryUnmarshaler, and is read with UnmarshalText.
+type textPair struct {
+	Key   string
+	Value string
+}
+
+func (p *textPair) UnmarshalText(text []byte) error {
+	k, v, ok := strings.Cut(string(text), "=")
+	if !ok {
+		return fmt.Errorf("missing =")
+	}
+	p.Key, p.Value = k, v
+	return nil
+}
+
+func (p *textPair) UnmarshalBinary(data []byte) error {
+	return fmt.Errorf("binary not supported")
+}
+
+func (b *blob) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty blob")
 	}
+	b.Data = append([]byte(nil), data...)
+	return nil
 }
 
 type value struct {
@@ -505,45 +2603,482 @@ func check(t *testing.T, got interface{}, values []value) {
 			}
 		}
 		got := field.Interface()
-		if diff := cmp.Diff(got, val.value); diff != "" {
+		if diff := cmp.Diff(got, val.value, valueComparers); diff != "" {
 			t.Errorf("%s (-got +want)\n%s", val.path, diff)
 		}
 	}
 }
 
+// valueComparers compares values of types with unexported fields: netip
+// values with ==, regexps by their pattern, time zones by name and math/big
+// numbers by value, with floats rounded to 18 decimals.
+var valueComparers = cmp.Options{
+	cmp.Comparer(func(a, b netip.Addr) bool { return a == b }),
+	cmp.Comparer(func(a, b netip.Prefix) bool { return a == b }),
+	cmp.Comparer(func(a, b regexp.Regexp) bool { return a.String() == b.String() }),
+	cmp.Comparer(func(a, b big.Int) bool { return a.Cmp(&b) == 0 }),
+	cmp.Comparer(func(a, b big.Float) bool { return a.Text('f', 18) == b.Text('f', 18) }),
+	cmp.Comparer(func(a, b big.Rat) bool { return a.Cmp(&b) == 0 }),
+	cmp.Comparer(func(a, b time.Location) bool { return a.String() == b.String() }),
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+func bigFloat(s string) *big.Float {
+	f, _, err := big.ParseFloat(s, 10, 128, big.ToNearestEven)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func bigInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big.Int " + s)
+	}
+	return n
+}
+
 type mockSSM struct {
-	params []ssm.Parameter
-	err    error
+	params       []types.Parameter
+	descriptions map[string]string
+	err          error
+
+	// delay is added to each GetParameters call.
+	delay time.Duration
+	// throttle is the number of GetParameters calls that fail with a
+	// ThrottlingException.
+	throttle int
+	// decryptFail is the number of GetParameters calls with decryption of
+	// SecureString parameters that fail with a KMS error.
+	decryptFail int
+	// deleteErr is returned by DeleteParameters for batches containing
+	// failDelete.
+	deleteErr  error
+	failDelete string
+	// keyIDs and labels hold the KMS key and labels of the latest version of
+	// each parameter.
+	keyIDs   map[string]string
+	labels   map[string][]string
+	labelErr error
+	// failLabel limits labelErr to the parameter with the name, if set.
+	failLabel string
+	// history holds every version of a parameter, with its labels. If set for
+	// a name, GetParameterHistory returns it instead of the latest version.
+	history map[string][]types.ParameterHistory
+	// selectors holds the version selected by name:label.
+	selectors map[string]int64
+	// tags holds the tags added to each parameter.
+	tags map[string]map[string]string
+
+	mu          sync.Mutex
+	puts        []*ssm.PutParameterInput
+	calls       int
+	singleCalls int
+	pathCalls   int
+	deleteCalls int
+	inFlight    int
+	maxInFlight int
+}
+
+func (m *mockSSM) getParams() []types.Parameter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.params
+}
+
+// setParam adds or replaces a parameter. The version is incremented.
+func (m *mockSSM) setParam(p types.Parameter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	params := make([]types.Parameter, 0, len(m.params)+1)
+	version := int64(1)
+	for _, existing := range m.params {
+		if *existing.Name == *p.Name {
+			version = existing.Version + 1
+			continue
+		}
+		params = append(params, existing)
+	}
+	p.Version = version
+	m.params = append(params, p)
 }
 
-func (m *mockSSM) GetParametersRequest(input *ssm.GetParametersInput) ssm.GetParametersRequest {
-	mockReq := &aws.Request{
-		HTTPRequest:  &http.Request{},
-		HTTPResponse: &http.Response{},
+func (m *mockSSM) GetParameters(ctx context.Context, input *ssm.GetParametersInput, _ ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	m.mu.Lock()
+	m.calls++
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
 	}
-	mockReq.Handlers.Send.PushBack(func(r *aws.Request) {
-		if m.err != nil {
-			r.Error = m.err
-			return
+	mockErr := m.err
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.inFlight--
+		m.mu.Unlock()
+	}()
+	if m.delay > 0 {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
-		var out []ssm.Parameter
-		for _, name := range input.Names {
-			for _, p := range m.params {
-				if *p.Name != name {
-					continue
-				}
-				if p.Type == ssm.ParameterTypeSecureString && !*input.WithDecryption {
-					p.Value = aws.String("<ENCRYPTED>")
-				}
-				out = append(out, p)
+	}
+	if mockErr != nil {
+		return nil, mockErr
+	}
+	m.mu.Lock()
+	throttled := m.throttle > 0
+	m.throttle--
+	m.mu.Unlock()
+	if throttled {
+		return nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	}
+	if len(input.Names) > 10 {
+		return nil, fmt.Errorf("ValidationException: too many names: %d", len(input.Names))
+	}
+	if *input.WithDecryption && m.hasSecure(input.Names) {
+		m.mu.Lock()
+		fail := m.decryptFail > 0
+		m.decryptFail--
+		m.mu.Unlock()
+		if fail {
+			return nil, &smithy.GenericAPIError{Code: "KMSInvalidStateException", Message: "Key is pending import"}
+		}
+	}
+	var out []types.Parameter
+	for _, name := range input.Names {
+		name, selector := splitSelector(name)
+		var (
+			found types.Parameter
+			ok    bool
+		)
+		for _, p := range m.getParams() {
+			if *p.Name != name && aws.ToString(p.ARN) != name {
+				continue
+			}
+			// Without a selector the latest version is returned
+			if selector != "" && !m.selects(name+selector, p.Version) || ok && p.Version < found.Version {
+				continue
+			}
+			if p.Type == types.ParameterTypeSecureString && !*input.WithDecryption {
+				p.Value = aws.String("<ENCRYPTED>")
+			}
+			if selector != "" {
+				p.Selector = aws.String(selector)
+			}
+			found, ok = p, true
+		}
+		if ok {
+			out = append(out, found)
+		}
+	}
+	return &ssm.GetParametersOutput{
+		Parameters: out,
+	}, nil
+}
+
+func (m *mockSSM) GetParameter(ctx context.Context, input *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	m.mu.Lock()
+	m.singleCalls++
+	m.mu.Unlock()
+	resp, err := m.GetParameters(ctx, &ssm.GetParametersInput{
+		Names:          []string{*input.Name},
+		WithDecryption: input.WithDecryption,
+	}, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Parameters) == 0 {
+		return nil, &smithy.GenericAPIError{Code: "ParameterNotFound", Message: "Parameter not found."}
+	}
+	return &ssm.GetParameterOutput{Parameter: &resp.Parameters[0]}, nil
+}
+
+// selects reports whether name:selector selects the version.
+func (m *mockSSM) selects(name string, version int64) bool {
+	if strings.HasSuffix(name, fmt.Sprintf(":%d", version)) {
+		return true
+	}
+	if v, ok := m.selectors[name]; ok {
+		return v == version
+	}
+	// Labels are looked up in the history, if set
+	base, selector := splitSelector(name)
+	for _, h := range m.history[base] {
+		if h.Version != version {
+			continue
+		}
+		for _, l := range h.Labels {
+			if ":"+l == selector {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasSecure reports whether any of the names is a SecureString parameter.
+func (m *mockSSM) hasSecure(names []string) bool {
+	for _, p := range m.getParams() {
+		for _, name := range names {
+			if *p.Name == name && p.Type == types.ParameterTypeSecureString {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *mockSSM) GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, _ ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	m.mu.Lock()
+	m.pathCalls++
+	m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	var matching []types.Parameter
+	for _, p := range m.getParams() {
+		if strings.HasPrefix(*p.Name, *input.Path+"/") {
+			if p.Type == types.ParameterTypeSecureString && !*input.WithDecryption {
+				p.Value = aws.String("<ENCRYPTED>")
+			}
+			matching = append(matching, p)
+		}
+	}
+	// Return two parameters per page
+	start := 0
+	if input.NextToken != nil {
+		start, _ = strconv.Atoi(*input.NextToken)
+	}
+	end := start + 2
+	out := &ssm.GetParametersByPathOutput{}
+	if end < len(matching) {
+		out.NextToken = aws.String(strconv.Itoa(end))
+	} else {
+		end = len(matching)
+	}
+	out.Parameters = matching[start:end]
+	return out, nil
+}
+
+func (m *mockSSM) DescribeParameters(ctx context.Context, input *ssm.DescribeParametersInput, _ ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out []types.ParameterMetadata
+	for _, f := range input.ParameterFilters {
+		for _, name := range f.Values {
+			if desc, ok := m.descriptions[name]; ok {
+				out = append(out, types.ParameterMetadata{
+					Name:        aws.String(name),
+					Description: aws.String(desc),
+				})
 			}
 		}
-		r.Data = &ssm.GetParametersOutput{
-			Parameters: out,
+	}
+	return &ssm.DescribeParametersOutput{
+		Parameters: out,
+	}, nil
+}
+
+func (m *mockSSM) PutParameter(ctx context.Context, input *ssm.PutParameterInput, _ ...func(*ssm.Options)) (*ssm.PutParameterOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.Lock()
+	m.puts = append(m.puts, input)
+	exists := false
+	for _, p := range m.params {
+		if *p.Name == *input.Name {
+			exists = true
 		}
+	}
+	m.mu.Unlock()
+	if exists && !aws.ToBool(input.Overwrite) {
+		return nil, &smithy.GenericAPIError{Code: "ParameterAlreadyExists", Message: "The parameter already exists."}
+	}
+	if len(input.Tags) > 0 && aws.ToBool(input.Overwrite) {
+		return nil, &smithy.GenericAPIError{Code: "ValidationException", Message: "tags and overwrite can't be used together."}
+	}
+	m.addTags(*input.Name, input.Tags)
+	m.setParam(types.Parameter{
+		Name:  input.Name,
+		Type:  input.Type,
+		Value: input.Value,
 	})
+	if input.KeyId != nil {
+		m.mu.Lock()
+		if m.keyIDs == nil {
+			m.keyIDs = make(map[string]string)
+		}
+		m.keyIDs[*input.Name] = *input.KeyId
+		m.mu.Unlock()
+	}
+	version := int64(0)
+	for _, p := range m.getParams() {
+		if *p.Name == *input.Name {
+			version = p.Version
+		}
+	}
+	return &ssm.PutParameterOutput{Version: version}, nil
+}
+
+func (m *mockSSM) DeleteParameters(ctx context.Context, input *ssm.DeleteParametersInput, _ ...func(*ssm.Options)) (*ssm.DeleteParametersOutput, error) {
+	if len(input.Names) > 10 {
+		return nil, fmt.Errorf("ValidationException: too many names: %d", len(input.Names))
+	}
+	for _, name := range input.Names {
+		if name == m.failDelete {
+			return nil, m.deleteErr
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteCalls++
+	out := &ssm.DeleteParametersOutput{}
+	for _, name := range input.Names {
+		found := false
+		params := make([]types.Parameter, 0, len(m.params))
+		for _, p := range m.params {
+			if *p.Name == name {
+				found = true
+				continue
+			}
+			params = append(params, p)
+		}
+		m.params = params
+		if found {
+			out.DeletedParameters = append(out.DeletedParameters, name)
+		} else {
+			out.InvalidParameters = append(out.InvalidParameters, name)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockSSM) GetParameterHistory(ctx context.Context, input *ssm.GetParameterHistoryInput, _ ...func(*ssm.Options)) (*ssm.GetParameterHistoryOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.mu.Lock()
+	history, ok := m.history[*input.Name]
+	m.mu.Unlock()
+	if ok {
+		return &ssm.GetParameterHistoryOutput{Parameters: history}, nil
+	}
+	for _, p := range m.getParams() {
+		if *p.Name != *input.Name {
+			continue
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		h := types.ParameterHistory{
+			Name:    p.Name,
+			Type:    p.Type,
+			Value:   p.Value,
+			Version: p.Version,
+			Labels:  m.labels[*p.Name],
+		}
+		if key, ok := m.keyIDs[*p.Name]; ok {
+			h.KeyId = aws.String(key)
+		}
+		return &ssm.GetParameterHistoryOutput{
+			Parameters: []types.ParameterHistory{h},
+		}, nil
+	}
+	return nil, &smithy.GenericAPIError{Code: "ParameterNotFound", Message: "Parameter not found."}
+}
+
+func (m *mockSSM) LabelParameterVersion(ctx context.Context, input *ssm.LabelParameterVersionInput, _ ...func(*ssm.Options)) (*ssm.LabelParameterVersionOutput, error) {
+	if m.labelErr != nil && (m.failLabel == "" || m.failLabel == *input.Name) {
+		return nil, m.labelErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if history, ok := m.history[*input.Name]; ok {
+		// Labels are moved from the version they were on
+		for i := range history {
+			var labels []string
+		next:
+			for _, l := range history[i].Labels {
+				for _, moved := range input.Labels {
+					if l == moved {
+						continue next
+					}
+				}
+				labels = append(labels, l)
+			}
+			if history[i].Version == *input.ParameterVersion {
+				labels = append(labels, input.Labels...)
+			}
+			history[i].Labels = labels
+		}
+		return &ssm.LabelParameterVersionOutput{ParameterVersion: *input.ParameterVersion}, nil
+	}
+	if m.labels == nil {
+		m.labels = make(map[string][]string)
+	}
+	m.labels[*input.Name] = append(m.labels[*input.Name], input.Labels...)
+	return &ssm.LabelParameterVersionOutput{
+		ParameterVersion: aws.ToInt64(input.ParameterVersion),
+	}, nil
+}
+
+func (m *mockSSM) UnlabelParameterVersion(ctx context.Context, input *ssm.UnlabelParameterVersionInput, _ ...func(*ssm.Options)) (*ssm.UnlabelParameterVersionOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var removed []string
+	history := m.history[*input.Name]
+	for i := range history {
+		if history[i].Version != *input.ParameterVersion {
+			continue
+		}
+		var labels []string
+	next:
+		for _, l := range history[i].Labels {
+			for _, r := range input.Labels {
+				if l == r {
+					removed = append(removed, l)
+					continue next
+				}
+			}
+			labels = append(labels, l)
+		}
+		history[i].Labels = labels
+	}
+	return &ssm.UnlabelParameterVersionOutput{RemovedLabels: removed}, nil
+}
+
+func (m *mockSSM) AddTagsToResource(ctx context.Context, input *ssm.AddTagsToResourceInput, _ ...func(*ssm.Options)) (*ssm.AddTagsToResourceOutput, error) {
+	if input.ResourceType != types.ResourceTypeForTaggingParameter {
+		return nil, fmt.Errorf("ValidationException: resource type %s", input.ResourceType)
+	}
+	m.addTags(*input.ResourceId, input.Tags)
+	return &ssm.AddTagsToResourceOutput{}, nil
+}
 
-	return ssm.GetParametersRequest{
-		Request: mockReq,
+func (m *mockSSM) addTags(name string, tags []types.Tag) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(tags) == 0 {
+		return
+	}
+	if m.tags == nil {
+		m.tags = make(map[string]map[string]string)
+	}
+	if m.tags[name] == nil {
+		m.tags[name] = make(map[string]string)
+	}
+	for _, t := range tags {
+		m.tags[name][*t.Key] = *t.Value
 	}
 }
@@ -0,0 +1,33 @@
+// Package ssmdecimal adds support for reading decimal.Decimal values from SSM
+// Parameter Store, for amounts such as prices and rates that must not lose
+// precision:
+//
+//   ps, err := ssm.NewParamStore(ssmdecimal.WithParseDecimal())
+//
+//   type Config struct {
+//       FeeRate decimal.Decimal `ssm:"fee_rate"`
+//   }
+//
+// The package is a separate module, so only programs that import it depend on
+// github.com/shopspring/decimal.
+package ssmdecimal
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/akupila/ssm"
+	"github.com/shopspring/decimal"
+)
+
+// WithParseDecimal parses a string to a decimal.Decimal. The value is parsed
+// exactly, without passing through a float.
+func WithParseDecimal() ssm.Option {
+	return ssm.WithConverter(reflect.TypeOf(decimal.Decimal{}), func(value string) (interface{}, error) {
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			return nil, fmt.Errorf("parse %q as decimal: %v", value, err)
+		}
+		return d, nil
+	})
+}
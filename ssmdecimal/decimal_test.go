@@ -0,0 +1,85 @@
+package ssmdecimal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akupila/ssm"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsssm "github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+	"github.com/shopspring/decimal"
+)
+
+func TestWithParseDecimal(t *testing.T) {
+	var cfg struct {
+		Limit  decimal.Decimal   `ssm:"limit"`
+		Limits []decimal.Decimal `ssm:"limits"`
+	}
+
+	mock := mockSSM{params: map[string]types.Parameter{
+		"/limit":  {Type: types.ParameterTypeString, Value: aws.String("1234.5678")},
+		"/limits": {Type: types.ParameterTypeStringList, Value: aws.String("0.1,0.2")},
+	}}
+	ps, err := ssm.NewParamStore(ssm.WithClient(mock), WithParseDecimal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := decimal.RequireFromString("1234.5678"); !cfg.Limit.Equal(want) {
+		t.Errorf("Limit = %s, want %s", cfg.Limit, want)
+	}
+	if len(cfg.Limits) != 2 || !cfg.Limits[0].Add(cfg.Limits[1]).Equal(decimal.RequireFromString("0.3")) {
+		t.Errorf("Limits = %v, want [0.1 0.2]", cfg.Limits)
+	}
+}
+
+func TestWithParseDecimal_invalid(t *testing.T) {
+	var cfg struct {
+		Limit decimal.Decimal `ssm:"limit"`
+	}
+
+	mock := mockSSM{params: map[string]types.Parameter{
+		"/limit": {Type: types.ParameterTypeString, Value: aws.String("ten")},
+	}}
+	ps, err := ssm.NewParamStore(ssm.WithClient(mock), WithParseDecimal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ps.Read(context.Background(), &cfg)
+	if err == nil {
+		t.Fatal("Want error")
+	}
+	t.Logf("Got expected error: %v", err)
+}
+
+type mockSSM struct {
+	ssm.Client // Other methods are not implemented
+	params     map[string]types.Parameter
+}
+
+func (m mockSSM) GetParameter(ctx context.Context, input *awsssm.GetParameterInput, _ ...func(*awsssm.Options)) (*awsssm.GetParameterOutput, error) {
+	p, ok := m.params[*input.Name]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "ParameterNotFound", Message: "Parameter not found."}
+	}
+	p.Name = input.Name
+	return &awsssm.GetParameterOutput{Parameter: &p}, nil
+}
+
+func (m mockSSM) GetParameters(ctx context.Context, input *awsssm.GetParametersInput, _ ...func(*awsssm.Options)) (*awsssm.GetParametersOutput, error) {
+	var out []types.Parameter
+	for _, name := range input.Names {
+		if p, ok := m.params[name]; ok {
+			p.Name = aws.String(name)
+			out = append(out, p)
+		}
+	}
+	return &awsssm.GetParametersOutput{
+		Parameters: out,
+	}, nil
+}
@@ -0,0 +1,40 @@
+// Package ssmuuid adds support for reading uuid.UUID values from SSM Parameter
+// Store. Values are accepted in any form uuid.Parse accepts, such as with or
+// without hyphens or a urn:uuid: prefix.
+//
+// It is a separate module, so github.com/google/uuid is not required by
+// programs that only import github.com/akupila/ssm.
+package ssmuuid
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/akupila/ssm"
+	"github.com/google/uuid"
+)
+
+// WithParseUUID parses a string to a uuid.UUID. uuid.NullUUID is also
+// supported, with Valid set when the parameter exists.
+func WithParseUUID() ssm.Option {
+	return func(s *ssm.ParamStore) {
+		ssm.WithConverter(reflect.TypeOf(uuid.UUID{}), func(value string) (interface{}, error) {
+			return parse(value)
+		})(s)
+		ssm.WithConverter(reflect.TypeOf(uuid.NullUUID{}), func(value string) (interface{}, error) {
+			id, err := parse(value)
+			if err != nil {
+				return nil, err
+			}
+			return uuid.NullUUID{UUID: id, Valid: true}, nil
+		})(s)
+	}
+}
+
+func parse(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parse %q as uuid: %v", value, err)
+	}
+	return id, nil
+}
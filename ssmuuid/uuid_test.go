@@ -0,0 +1,93 @@
+package ssmuuid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/akupila/ssm"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsssm "github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/uuid"
+)
+
+func TestWithParseUUID(t *testing.T) {
+	var cfg struct {
+		TenantID  uuid.UUID     `ssm:"tenant_id"`
+		ClientID  uuid.NullUUID `ssm:"client_id"`
+		Allowlist []uuid.UUID   `ssm:"allowlist"`
+	}
+
+	mock := mockSSM{params: map[string]types.Parameter{
+		"/tenant_id": {Type: types.ParameterTypeString, Value: aws.String("6ba7b810-9dad-11d1-80b4-00c04fd430c8")},
+		"/client_id": {Type: types.ParameterTypeSecureString, Value: aws.String("6ba7b811-9dad-11d1-80b4-00c04fd430c8")},
+		"/allowlist": {Type: types.ParameterTypeStringList, Value: aws.String("6ba7b812-9dad-11d1-80b4-00c04fd430c8")},
+	}}
+	ps, err := ssm.NewParamStore(ssm.WithClient(mock), WithParseUUID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"); cfg.TenantID != want {
+		t.Errorf("TenantID = %s, want %s", cfg.TenantID, want)
+	}
+	if want := uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8"); !cfg.ClientID.Valid || cfg.ClientID.UUID != want {
+		t.Errorf("ClientID = %+v, want %s", cfg.ClientID, want)
+	}
+	if want := uuid.MustParse("6ba7b812-9dad-11d1-80b4-00c04fd430c8"); len(cfg.Allowlist) != 1 || cfg.Allowlist[0] != want {
+		t.Errorf("Allowlist = %v, want [%s]", cfg.Allowlist, want)
+	}
+}
+
+func TestWithParseUUID_invalid(t *testing.T) {
+	var cfg struct {
+		TenantID uuid.UUID `ssm:"tenant_id"`
+	}
+
+	mock := mockSSM{params: map[string]types.Parameter{
+		"/tenant_id": {Type: types.ParameterTypeString, Value: aws.String("not-a-uuid")},
+	}}
+	ps, err := ssm.NewParamStore(ssm.WithClient(mock), WithParseUUID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ps.Read(context.Background(), &cfg)
+	if err == nil {
+		t.Fatal("Want error")
+	}
+	if !strings.Contains(err.Error(), "/tenant_id") {
+		t.Errorf("Error %q does not name the parameter", err)
+	}
+}
+
+type mockSSM struct {
+	ssm.Client // Other methods are not implemented
+	params     map[string]types.Parameter
+}
+
+func (m mockSSM) GetParameter(ctx context.Context, input *awsssm.GetParameterInput, _ ...func(*awsssm.Options)) (*awsssm.GetParameterOutput, error) {
+	p, ok := m.params[*input.Name]
+	if !ok {
+		return nil, &smithy.GenericAPIError{Code: "ParameterNotFound", Message: "Parameter not found."}
+	}
+	p.Name = input.Name
+	return &awsssm.GetParameterOutput{Parameter: &p}, nil
+}
+
+func (m mockSSM) GetParameters(ctx context.Context, input *awsssm.GetParametersInput, _ ...func(*awsssm.Options)) (*awsssm.GetParametersOutput, error) {
+	var out []types.Parameter
+	for _, name := range input.Names {
+		if p, ok := m.params[name]; ok {
+			p.Name = aws.String(name)
+			out = append(out, p)
+		}
+	}
+	return &awsssm.GetParametersOutput{
+		Parameters: out,
+	}, nil
+}
@@ -0,0 +1,296 @@
+// Package ssmv1 adapts an SSM client from aws-sdk-go (v1) to an ssm.Client,
+// for programs that have not migrated to aws-sdk-go-v2:
+//
+//   sess := session.Must(session.NewSession())
+//   ps, err := ssm.NewParamStore(ssm.WithClient(ssmv1.Wrap(awsssm.New(sess))))
+//
+// Errors returned by the v1 client implement smithy.APIError, so throttling
+// errors are retried when ssm.WithRetry is passed. The awserr.Error they wrap
+// is returned by errors.As.
+package ssmv1
+
+import (
+	"context"
+
+	"github.com/akupila/ssm"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ssmv2 "github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsssm "github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/aws/smithy-go"
+)
+
+// Wrap returns an ssm.Client that makes requests using the v1 client.
+//
+// Options passed to the ssm.Client methods apply to v2 clients and are
+// ignored.
+func Wrap(client ssmiface.SSMAPI) ssm.Client {
+	return &adapter{client: client}
+}
+
+type adapter struct {
+	client ssmiface.SSMAPI
+}
+
+func (a *adapter) GetParameter(ctx context.Context, input *ssmv2.GetParameterInput, _ ...func(*ssmv2.Options)) (*ssmv2.GetParameterOutput, error) {
+	resp, err := a.client.GetParameterWithContext(ctx, &awsssm.GetParameterInput{
+		Name:           input.Name,
+		WithDecryption: input.WithDecryption,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	if resp.Parameter == nil {
+		return &ssmv2.GetParameterOutput{}, nil
+	}
+	p := parameters([]*awsssm.Parameter{resp.Parameter})[0]
+	return &ssmv2.GetParameterOutput{Parameter: &p}, nil
+}
+
+func (a *adapter) GetParameters(ctx context.Context, input *ssmv2.GetParametersInput, _ ...func(*ssmv2.Options)) (*ssmv2.GetParametersOutput, error) {
+	resp, err := a.client.GetParametersWithContext(ctx, &awsssm.GetParametersInput{
+		Names:          stringSlice(input.Names),
+		WithDecryption: input.WithDecryption,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ssmv2.GetParametersOutput{
+		Parameters:        parameters(resp.Parameters),
+		InvalidParameters: aws.ToStringSlice(resp.InvalidParameters),
+	}, nil
+}
+
+func (a *adapter) GetParametersByPath(ctx context.Context, input *ssmv2.GetParametersByPathInput, _ ...func(*ssmv2.Options)) (*ssmv2.GetParametersByPathOutput, error) {
+	resp, err := a.client.GetParametersByPathWithContext(ctx, &awsssm.GetParametersByPathInput{
+		Path:           input.Path,
+		Recursive:      input.Recursive,
+		WithDecryption: input.WithDecryption,
+		MaxResults:     maxResults(input.MaxResults),
+		NextToken:      input.NextToken,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ssmv2.GetParametersByPathOutput{
+		Parameters: parameters(resp.Parameters),
+		NextToken:  resp.NextToken,
+	}, nil
+}
+
+func (a *adapter) DescribeParameters(ctx context.Context, input *ssmv2.DescribeParametersInput, _ ...func(*ssmv2.Options)) (*ssmv2.DescribeParametersOutput, error) {
+	filters := make([]*awsssm.ParameterStringFilter, len(input.ParameterFilters))
+	for i, f := range input.ParameterFilters {
+		filters[i] = &awsssm.ParameterStringFilter{
+			Key:    f.Key,
+			Option: f.Option,
+			Values: stringSlice(f.Values),
+		}
+	}
+	resp, err := a.client.DescribeParametersWithContext(ctx, &awsssm.DescribeParametersInput{
+		ParameterFilters: filters,
+		MaxResults:       maxResults(input.MaxResults),
+		NextToken:        input.NextToken,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	out := &ssmv2.DescribeParametersOutput{
+		Parameters: make([]types.ParameterMetadata, len(resp.Parameters)),
+		NextToken:  resp.NextToken,
+	}
+	for i, p := range resp.Parameters {
+		out.Parameters[i] = types.ParameterMetadata{
+			Name:        p.Name,
+			Description: p.Description,
+			Type:        types.ParameterType(aws.ToString(p.Type)),
+			Version:     aws.ToInt64(p.Version),
+		}
+	}
+	return out, nil
+}
+
+func (a *adapter) PutParameter(ctx context.Context, input *ssmv2.PutParameterInput, _ ...func(*ssmv2.Options)) (*ssmv2.PutParameterOutput, error) {
+	tags := tagSlice(input.Tags)
+	in := &awsssm.PutParameterInput{
+		Name:           input.Name,
+		Value:          input.Value,
+		Description:    input.Description,
+		AllowedPattern: input.AllowedPattern,
+		DataType:       input.DataType,
+		KeyId:          input.KeyId,
+		Overwrite:      input.Overwrite,
+	}
+	if input.Type != "" {
+		in.Type = aws.String(string(input.Type))
+	}
+	if input.Tier != "" {
+		in.Tier = aws.String(string(input.Tier))
+	}
+	if len(tags) > 0 {
+		in.Tags = tags
+	}
+	resp, err := a.client.PutParameterWithContext(ctx, in)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ssmv2.PutParameterOutput{
+		Version: aws.ToInt64(resp.Version),
+		Tier:    types.ParameterTier(aws.ToString(resp.Tier)),
+	}, nil
+}
+
+func (a *adapter) DeleteParameters(ctx context.Context, input *ssmv2.DeleteParametersInput, _ ...func(*ssmv2.Options)) (*ssmv2.DeleteParametersOutput, error) {
+	resp, err := a.client.DeleteParametersWithContext(ctx, &awsssm.DeleteParametersInput{
+		Names: stringSlice(input.Names),
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ssmv2.DeleteParametersOutput{
+		DeletedParameters: aws.ToStringSlice(resp.DeletedParameters),
+		InvalidParameters: aws.ToStringSlice(resp.InvalidParameters),
+	}, nil
+}
+
+func (a *adapter) GetParameterHistory(ctx context.Context, input *ssmv2.GetParameterHistoryInput, _ ...func(*ssmv2.Options)) (*ssmv2.GetParameterHistoryOutput, error) {
+	resp, err := a.client.GetParameterHistoryWithContext(ctx, &awsssm.GetParameterHistoryInput{
+		Name:           input.Name,
+		WithDecryption: input.WithDecryption,
+		MaxResults:     maxResults(input.MaxResults),
+		NextToken:      input.NextToken,
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	out := &ssmv2.GetParameterHistoryOutput{
+		Parameters: make([]types.ParameterHistory, len(resp.Parameters)),
+		NextToken:  resp.NextToken,
+	}
+	for i, p := range resp.Parameters {
+		out.Parameters[i] = types.ParameterHistory{
+			AllowedPattern:   p.AllowedPattern,
+			DataType:         p.DataType,
+			Description:      p.Description,
+			KeyId:            p.KeyId,
+			Labels:           aws.ToStringSlice(p.Labels),
+			LastModifiedDate: p.LastModifiedDate,
+			LastModifiedUser: p.LastModifiedUser,
+			Name:             p.Name,
+			Tier:             types.ParameterTier(aws.ToString(p.Tier)),
+			Type:             types.ParameterType(aws.ToString(p.Type)),
+			Value:            p.Value,
+			Version:          aws.ToInt64(p.Version),
+		}
+	}
+	return out, nil
+}
+
+func (a *adapter) LabelParameterVersion(ctx context.Context, input *ssmv2.LabelParameterVersionInput, _ ...func(*ssmv2.Options)) (*ssmv2.LabelParameterVersionOutput, error) {
+	resp, err := a.client.LabelParameterVersionWithContext(ctx, &awsssm.LabelParameterVersionInput{
+		Name:             input.Name,
+		ParameterVersion: input.ParameterVersion,
+		Labels:           stringSlice(input.Labels),
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ssmv2.LabelParameterVersionOutput{
+		InvalidLabels:    aws.ToStringSlice(resp.InvalidLabels),
+		ParameterVersion: aws.ToInt64(resp.ParameterVersion),
+	}, nil
+}
+
+func (a *adapter) UnlabelParameterVersion(ctx context.Context, input *ssmv2.UnlabelParameterVersionInput, _ ...func(*ssmv2.Options)) (*ssmv2.UnlabelParameterVersionOutput, error) {
+	resp, err := a.client.UnlabelParameterVersionWithContext(ctx, &awsssm.UnlabelParameterVersionInput{
+		Name:             input.Name,
+		ParameterVersion: input.ParameterVersion,
+		Labels:           stringSlice(input.Labels),
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ssmv2.UnlabelParameterVersionOutput{
+		InvalidLabels: aws.ToStringSlice(resp.InvalidLabels),
+		RemovedLabels: aws.ToStringSlice(resp.RemovedLabels),
+	}, nil
+}
+
+func (a *adapter) AddTagsToResource(ctx context.Context, input *ssmv2.AddTagsToResourceInput, _ ...func(*ssmv2.Options)) (*ssmv2.AddTagsToResourceOutput, error) {
+	_, err := a.client.AddTagsToResourceWithContext(ctx, &awsssm.AddTagsToResourceInput{
+		ResourceType: aws.String(string(input.ResourceType)),
+		ResourceId:   input.ResourceId,
+		Tags:         tagSlice(input.Tags),
+	})
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &ssmv2.AddTagsToResourceOutput{}, nil
+}
+
+func parameters(params []*awsssm.Parameter) []types.Parameter {
+	out := make([]types.Parameter, len(params))
+	for i, p := range params {
+		out[i] = types.Parameter{
+			ARN:              p.ARN,
+			DataType:         p.DataType,
+			LastModifiedDate: p.LastModifiedDate,
+			Name:             p.Name,
+			Selector:         p.Selector,
+			SourceResult:     p.SourceResult,
+			Type:             types.ParameterType(aws.ToString(p.Type)),
+			Value:            p.Value,
+			Version:          aws.ToInt64(p.Version),
+		}
+	}
+	return out
+}
+
+func stringSlice(s []string) []*string {
+	out := make([]*string, len(s))
+	for i := range s {
+		out[i] = &s[i]
+	}
+	return out
+}
+
+func tagSlice(tags []types.Tag) []*awsssm.Tag {
+	out := make([]*awsssm.Tag, len(tags))
+	for i, t := range tags {
+		out[i] = &awsssm.Tag{Key: t.Key, Value: t.Value}
+	}
+	return out
+}
+
+func maxResults(n *int32) *int64 {
+	if n == nil {
+		return nil
+	}
+	return aws.Int64(int64(*n))
+}
+
+// apiError converts an awserr.Error to a smithy.APIError that wraps it.
+func apiError(err error) error {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return err
+	}
+	return &wrappedError{
+		GenericAPIError: smithy.GenericAPIError{
+			Code:    aerr.Code(),
+			Message: aerr.Message(),
+		},
+		err: aerr,
+	}
+}
+
+// wrappedError is a smithy.APIError that keeps the awserr.Error it was
+// converted from, so it can still be matched with errors.As.
+type wrappedError struct {
+	smithy.GenericAPIError
+	err awserr.Error
+}
+
+func (e *wrappedError) Unwrap() error { return e.err }
@@ -0,0 +1,211 @@
+package ssmv1
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akupila/ssm"
+	ssmv2 "github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awsssm "github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/aws/smithy-go"
+)
+
+func TestWrap(t *testing.T) {
+	mock := &mockSSM{
+		params: map[string]*awsssm.Parameter{
+			"/app/host":     {Type: aws.String("String"), Value: aws.String("localhost")},
+			"/app/password": {Type: aws.String("SecureString"), Value: aws.String("secret"), Version: aws.Int64(3)},
+			"/app/peers":    {Type: aws.String("StringList"), Value: aws.String("a,b")},
+		},
+		throttle: 1,
+	}
+	ps, err := ssm.NewParamStore(
+		ssm.WithClient(Wrap(mock)),
+		ssm.WithPrefix("app"),
+		ssm.WithRetry(2, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host     string   `ssm:"host"`
+		Password string   `ssm:"password"`
+		Peers    []string `ssm:"peers"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "localhost" || cfg.Password != "secret" || strings.Join(cfg.Peers, ",") != "a,b" {
+		t.Errorf("Read() = %+v", cfg)
+	}
+	if mock.calls != 2 {
+		t.Errorf("GetParameters called %d times, want 2 (throttled once)", mock.calls)
+	}
+}
+
+func TestWrap_pathFetch(t *testing.T) {
+	mock := &mockSSM{
+		params: map[string]*awsssm.Parameter{
+			"/app/host": {Type: aws.String("String"), Value: aws.String("localhost")},
+			"/app/port": {Type: aws.String("String"), Value: aws.String("8080")},
+		},
+	}
+	ps, err := ssm.NewParamStore(
+		ssm.WithClient(Wrap(mock)),
+		ssm.WithPrefix("app"),
+		ssm.WithPathFetch(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+		Port string `ssm:"port"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "localhost" || cfg.Port != "8080" {
+		t.Errorf("Read() = %+v", cfg)
+	}
+	if mock.calls != 0 {
+		t.Errorf("GetParameters called %d times, want 0", mock.calls)
+	}
+}
+
+func TestWrap_descriptions(t *testing.T) {
+	mock := &mockSSM{
+		params: map[string]*awsssm.Parameter{
+			"/timeout": {Type: aws.String("String"), Value: aws.String("30")},
+		},
+		descriptions: map[string]string{
+			"/timeout": "duration, e.g. 30s",
+		},
+	}
+	ps, err := ssm.NewParamStore(
+		ssm.WithClient(Wrap(mock)),
+		ssm.WithParseDuration(),
+		ssm.WithDescriptions(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Timeout time.Duration `ssm:"timeout"`
+	}
+	err = ps.Read(context.Background(), &cfg)
+	if err == nil {
+		t.Fatal("Want error")
+	}
+	if !strings.Contains(err.Error(), "(duration, e.g. 30s)") {
+		t.Errorf("Error %q does not include the description", err)
+	}
+}
+
+func TestWrap_nilParameter(t *testing.T) {
+	mock := &mockSSM{
+		params: map[string]*awsssm.Parameter{"/app/host": nil},
+	}
+	resp, err := Wrap(mock).GetParameter(context.Background(), &ssmv2.GetParameterInput{
+		Name: aws.String("/app/host"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Parameter != nil {
+		t.Errorf("Parameter = %+v, want nil", resp.Parameter)
+	}
+}
+
+func TestWrap_error(t *testing.T) {
+	mock := &mockSSM{}
+	_, err := Wrap(mock).GetParameter(context.Background(), &ssmv2.GetParameterInput{
+		Name: aws.String("/app/host"),
+	})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != awsssm.ErrCodeParameterNotFound {
+		t.Errorf("GetParameter() err = %v, want smithy.APIError %s", err, awsssm.ErrCodeParameterNotFound)
+	}
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || awsErr.Code() != awsssm.ErrCodeParameterNotFound {
+		t.Errorf("GetParameter() err = %v, want awserr.Error %s", err, awsssm.ErrCodeParameterNotFound)
+	}
+}
+
+type mockSSM struct {
+	ssmiface.SSMAPI // Other methods are not implemented
+	params          map[string]*awsssm.Parameter
+	descriptions    map[string]string
+
+	// throttle is the number of GetParameters calls that fail with a
+	// ThrottlingException.
+	throttle int
+	calls    int
+}
+
+func (m *mockSSM) GetParameterWithContext(ctx aws.Context, input *awsssm.GetParameterInput, _ ...request.Option) (*awsssm.GetParameterOutput, error) {
+	m.calls++
+	p, ok := m.params[*input.Name]
+	if !ok {
+		return nil, awserr.New(awsssm.ErrCodeParameterNotFound, "Parameter not found", nil)
+	}
+	if p == nil {
+		return &awsssm.GetParameterOutput{}, nil
+	}
+	p.Name = input.Name
+	return &awsssm.GetParameterOutput{Parameter: p}, nil
+}
+
+func (m *mockSSM) GetParametersWithContext(ctx aws.Context, input *awsssm.GetParametersInput, _ ...request.Option) (*awsssm.GetParametersOutput, error) {
+	m.calls++
+	if m.throttle > 0 {
+		m.throttle--
+		return nil, awserr.New("ThrottlingException", "Rate exceeded", nil)
+	}
+	out := &awsssm.GetParametersOutput{}
+	for _, name := range input.Names {
+		if p, ok := m.params[*name]; ok {
+			p.Name = name
+			out.Parameters = append(out.Parameters, p)
+		} else {
+			out.InvalidParameters = append(out.InvalidParameters, name)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockSSM) GetParametersByPathWithContext(ctx aws.Context, input *awsssm.GetParametersByPathInput, _ ...request.Option) (*awsssm.GetParametersByPathOutput, error) {
+	out := &awsssm.GetParametersByPathOutput{}
+	for name, p := range m.params {
+		if strings.HasPrefix(name, *input.Path+"/") {
+			p.Name = aws.String(name)
+			out.Parameters = append(out.Parameters, p)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockSSM) DescribeParametersWithContext(ctx aws.Context, input *awsssm.DescribeParametersInput, _ ...request.Option) (*awsssm.DescribeParametersOutput, error) {
+	out := &awsssm.DescribeParametersOutput{}
+	for _, f := range input.ParameterFilters {
+		for _, name := range f.Values {
+			if desc, ok := m.descriptions[*name]; ok {
+				out.Parameters = append(out.Parameters, &awsssm.ParameterMetadata{
+					Name:        name,
+					Description: aws.String(desc),
+				})
+			}
+		}
+	}
+	return out, nil
+}
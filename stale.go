@@ -0,0 +1,127 @@
+package ssm
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// staleEntry is the last value fetched of a parameter.
+type staleEntry struct {
+	param types.Parameter
+	// missing is set if the parameter did not exist, so optional parameters
+	// can be served as missing.
+	missing bool
+	fetched time.Time
+}
+
+// WithStaleWhileRevalidate keeps serving the last values read if SSM is
+// unavailable. Once all parameters of a config have been read, later reads
+// return the values read before and refresh them in the background, so Read
+// does not fail or wait for SSM.
+//
+// Values are refreshed when they are older than the TTL passed to WithCache
+// or WithSharedCache, or set with the ttl= tag option, or on every read if no
+// TTL was set. Errors while refreshing are passed to onError, if not nil, and
+// logged.
+//
+// The first read of a config is made synchronously and returns errors as
+// usual.
+func WithStaleWhileRevalidate(onError func(err error)) Option {
+	return func(s *ParamStore) {
+		s.staleWhileRevalidate = true
+		s.onRevalidateError = onError
+	}
+}
+
+// fetchStale gets the parameters with the given names, serving the last values
+// fetched and refreshing them in the background if WithStaleWhileRevalidate
+// was passed.
+func (s *ParamStore) fetchStale(ctx context.Context, names []string) ([]types.Parameter, error) {
+	if paths, _ := splitPaths(names); !s.staleWhileRevalidate || len(paths) > 0 {
+		// The parameters under the paths of map fields are not known, so
+		// they cannot be served from the stale values.
+		return s.fetchShared(ctx, names)
+	}
+
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = s.cacheKey(ctx, name)
+	}
+	key := strings.Join(keys, "\x00")
+	s.mu.Lock()
+	params := make([]types.Parameter, 0, len(names))
+	expired := false
+	now := s.now()
+	for i, name := range names {
+		e, ok := s.stale[keys[i]]
+		if !ok {
+			params = nil
+			break
+		}
+		if !e.missing {
+			params = append(params, e.param)
+		}
+		if !now.Before(e.fetched.Add(s.ttl(ctx, name))) {
+			expired = true
+		}
+	}
+	revalidate := params != nil && expired && !s.revalidating[key]
+	if revalidate {
+		s.revalidating[key] = true
+	}
+	s.mu.Unlock()
+
+	if params == nil {
+		params, err := s.fetchShared(ctx, names)
+		if err != nil {
+			// A partial result is not stored, as the missing names would
+			// be served as not found.
+			return params, err
+		}
+		s.storeStale(ctx, names, params)
+		return params, nil
+	}
+	if revalidate {
+		go s.revalidate(detachReadOptions(ctx), key, names)
+	}
+	recordSource(ctx, SourceStale, names...)
+	return params, nil
+}
+
+// revalidate fetches the parameters from SSM, replacing the stale values.
+func (s *ParamStore) revalidate(ctx context.Context, key string, names []string) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.revalidating, key)
+		s.mu.Unlock()
+	}()
+
+	ctx = BypassCache(ctx)
+	params, err := s.fetchShared(ctx, names)
+	if err != nil {
+		s.log(ctx, slog.LevelWarn, "revalidate parameters", slog.String("error", err.Error()))
+		if s.onRevalidateError != nil {
+			s.onRevalidateError(err)
+		}
+		return
+	}
+	s.storeStale(ctx, names, params)
+}
+
+// storeStale records the parameters as the last values fetched of the names
+// requested by the read of ctx.
+func (s *ParamStore) storeStale(ctx context.Context, names []string, params []types.Parameter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for _, name := range names {
+		s.stale[s.cacheKey(ctx, name)] = staleEntry{missing: true, fetched: now}
+	}
+	for _, p := range params {
+		s.stale[s.cacheKey(ctx, *p.Name)] = staleEntry{param: p, fetched: now}
+	}
+}
@@ -0,0 +1,146 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestWithStaleWhileRevalidate(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	onError := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+	numErrs := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs)
+	}
+
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithStaleWhileRevalidate(onError))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// SSM unavailable: the previous value is served and the error reported
+	mock.mu.Lock()
+	mock.err = errors.New("InternalServerError")
+	mock.mu.Unlock()
+	mock.setParam(stringParam("/a", "2"))
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "1"}})
+	waitFor(t, func() bool { return numErrs() == 1 })
+
+	// Available again: refreshed in the background
+	mock.mu.Lock()
+	mock.err = nil
+	mock.mu.Unlock()
+	waitFor(t, func() bool {
+		if err := ps.Read(context.Background(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		return cfg.A == "2"
+	})
+}
+
+func TestWithStaleWhileRevalidate_ttl(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithCache(time.Minute),
+		WithStaleWhileRevalidate(nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.now = func() time.Time { return now }
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	for i := 0; i < 3; i++ {
+		if err := ps.Read(context.Background(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+
+	// Expired: the stale value is returned while refreshing
+	mock.setParam(stringParam("/a", "2"))
+	now = now.Add(time.Minute)
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{{path: "A", value: "1"}})
+	waitFor(t, func() bool {
+		if err := ps.Read(context.Background(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		return cfg.A == "2"
+	})
+}
+
+func TestWithStaleWhileRevalidate_initialError(t *testing.T) {
+	mock := &mockSSM{err: errors.New("InternalServerError")}
+	ps, err := NewParamStore(WithClient(mock), WithStaleWhileRevalidate(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err == nil {
+		t.Fatal("Want error")
+	}
+}
+
+func TestWithStaleWhileRevalidate_optional(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/a", "1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Minute), WithStaleWhileRevalidate(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		A string `ssm:"a"`
+		B string `ssm:"b,optional"`
+	}
+	for i := 0; i < 3; i++ {
+		if err := ps.Read(context.Background(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(t, cfg, []value{{path: "A", value: "1"}, {path: "B", value: ""}})
+	if mock.calls != 1 {
+		t.Errorf("GetParameters called %d times, want 1", mock.calls)
+	}
+}
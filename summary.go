@@ -0,0 +1,95 @@
+package ssm
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A Summary describes a read, to be logged as a single line when a service
+// starts. It is returned by ReadWithSummary.
+type Summary struct {
+	// Params is the number of fields read.
+	Params int
+	// VersionsHash is a hash of the names and versions of the parameters
+	// read. It changes when any parameter changes, so it can be compared
+	// between instances to check that they run with the same config.
+	VersionsHash string
+	// Prefixes are the paths the parameters were read from, such as the
+	// prefix set with WithPrefix.
+	Prefixes []string
+	// Duration is the time the read took.
+	Duration time.Duration
+	// Cached is the number of fields read from the cache, including stale
+	// values.
+	Cached int
+	// Fetched is the number of fields read from SSM.
+	Fetched int
+	// Defaults is the number of fields set to their default value.
+	Defaults int
+}
+
+// ReadWithSummary reads configuration values into target like Read, and
+// returns a summary of the read. The summary implements slog.LogValuer:
+//
+//   sum, err := ps.ReadWithSummary(ctx, &cfg)
+//   if err != nil {
+//       return err
+//   }
+//   logger.Info("config loaded", "config", sum)
+//
+// is logged as:
+//
+//   level=INFO msg="config loaded" config.params=12 config.versions=3f2a9c0e1b7d config.prefixes=/prod/app config.duration=48ms config.cached=0 config.fetched=11 config.defaults=1
+func (s *ParamStore) ReadWithSummary(ctx context.Context, target interface{}) (Summary, error) {
+	start := time.Now()
+	report, err := s.ReadWithReport(ctx, target)
+	if err != nil {
+		return Summary{}, err
+	}
+	sum := Summary{
+		Params:   len(report.Fields),
+		Duration: time.Since(start),
+	}
+
+	prefixes := make(map[string]bool)
+	versions := make(map[string]int64)
+	for _, f := range report.Fields {
+		switch f.Source {
+		case SourceCache, SourceStale:
+			sum.Cached++
+		case SourceSSM:
+			sum.Fetched++
+		case SourceDefault:
+			sum.Defaults++
+		}
+		versions[f.Name] = f.Version
+		if f.Prefix != "" {
+			prefixes[f.Prefix] = true
+		} else {
+			prefixes[path.Dir(strings.TrimSuffix(f.Name, "/"))] = true
+		}
+	}
+	sum.VersionsHash = versionsHash(versions)
+	for p := range prefixes {
+		sum.Prefixes = append(sum.Prefixes, p)
+	}
+	sort.Strings(sum.Prefixes)
+	return sum, nil
+}
+
+// LogValue implements slog.LogValuer.
+func (s Summary) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("params", s.Params),
+		slog.String("versions", s.VersionsHash),
+		slog.String("prefixes", strings.Join(s.Prefixes, ",")),
+		slog.Duration("duration", s.Duration),
+		slog.Int("cached", s.Cached),
+		slog.Int("fetched", s.Fetched),
+		slog.Int("defaults", s.Defaults),
+	)
+}
@@ -0,0 +1,91 @@
+package ssm
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParamStore_ReadWithSummary(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/app/host", "db.example.com"),
+		stringParam("/app/port", "5432"),
+		stringParam("/shared/region", "eu-west-1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithPrefix("app"), WithCache(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		Host   string `ssm:"host"`
+		Port   string `ssm:"port"`
+		Region string `ssm:"/shared/region"`
+		User   string `ssm:"user,default=admin"`
+	}
+	var cfg config
+	first, err := ps.ReadWithSummary(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Summary{
+		Params:   4,
+		Prefixes: []string{"/app", "/shared"},
+		Fetched:  3,
+		Defaults: 1,
+	}
+	ignore := cmpopts.IgnoreFields(Summary{}, "VersionsHash", "Duration")
+	if diff := cmp.Diff(first, want, ignore); diff != "" {
+		t.Errorf("First Summary (-got +want)\n%s", diff)
+	}
+
+	second, err := ps.ReadWithSummary(context.Background(), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Cached != 3 || second.Fetched != 0 {
+		t.Errorf("Second Summary cached = %d, fetched = %d, want 3, 0", second.Cached, second.Fetched)
+	}
+	if second.VersionsHash != first.VersionsHash {
+		t.Errorf("VersionsHash changed without a new version: %s != %s", second.VersionsHash, first.VersionsHash)
+	}
+
+	mock.setParam(stringParam("/app/host", "db2.example.com"))
+	third, err := ps.ReadWithSummary(BypassCache(context.Background()), &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.VersionsHash == first.VersionsHash {
+		t.Errorf("VersionsHash did not change with a new version")
+	}
+}
+
+func TestSummary_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("config loaded", "config", Summary{
+		Params:       2,
+		VersionsHash: "abc",
+		Prefixes:     []string{"/app", "/shared"},
+		Duration:     48 * time.Millisecond,
+		Fetched:      2,
+	})
+	want := `level=INFO msg="config loaded" config.params=2 config.versions=abc config.prefixes=/app,/shared config.duration=48ms config.cached=0 config.fetched=2 config.defaults=0`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("Log = %s\nwant %s", got, want)
+	}
+}
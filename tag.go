@@ -0,0 +1,130 @@
+package ssm
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tagOptions is the string following a comma in a struct tag, e.g. `ssm:"name,optional"`.
+type tagOptions string
+
+// parseTag splits a struct tag into its name and comma-separated options.
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+// Contains reports whether a comma-separated list of options contains a
+// particular option.
+func (o tagOptions) Contains(name string) bool {
+	for _, opt := range o.split() {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value of a key=value option. Values may be quoted with
+// single quotes to include commas, e.g. `ssm:"host,desc='host, with port'"`.
+func (o tagOptions) Get(key string) (string, bool) {
+	for _, opt := range o.split() {
+		if !strings.HasPrefix(opt, key+"=") {
+			continue
+		}
+		v := strings.TrimPrefix(opt, key+"=")
+		if len(v) >= 2 && strings.HasPrefix(v, "'") && strings.HasSuffix(v, "'") {
+			v = v[1 : len(v)-1]
+		}
+		return v, true
+	}
+	return "", false
+}
+
+// flagOptions are the tag options that are set without a value.
+var flagOptions = map[string]bool{
+	"deprecated": true,
+	"inline":     true,
+	"json":       true,
+	"kv":         true,
+	"nodecrypt":  true,
+	"optional":   true,
+	"root":       true,
+	"secure":     true,
+	"squash":     true,
+}
+
+// Envs returns the environments of the envs= option, separated by | or, within
+// quotes, by commas. An error is returned if the unquoted list is followed by
+// options that are not known, as in envs=prod,staging, where staging would
+// otherwise be ignored.
+func (o tagOptions) Envs() ([]string, bool, error) {
+	v, ok := o.Get("envs")
+	if !ok {
+		return nil, false, nil
+	}
+	opts := o.split()
+	for i, opt := range opts {
+		if !strings.HasPrefix(opt, "envs=") || strings.HasPrefix(opt, "envs='") {
+			continue
+		}
+		for _, next := range opts[i+1:] {
+			if strings.Contains(next, "=") {
+				break
+			}
+			if !flagOptions[next] {
+				return nil, true, fmt.Errorf("envs: unknown option %q, separate environments with |", next)
+			}
+		}
+		break
+	}
+	envs := strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '|' })
+	for i, env := range envs {
+		envs[i] = strings.TrimSpace(env)
+	}
+	return envs, true, nil
+}
+
+// split splits the options at commas that are not within single quotes.
+func (o tagOptions) split() []string {
+	var (
+		opts   []string
+		quoted bool
+		start  int
+	)
+	s := string(o)
+	for i, r := range s {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			opts = append(opts, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		opts = append(opts, s[start:])
+	}
+	return opts
+}
+
+// snakeCase converts a CamelCase field name to snake_case. Acronyms are kept
+// together, so ClientID becomes client_id and HTTPServer becomes http_server.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package ssm
+
+import "testing"
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		wantName string
+		wantOpts tagOptions
+	}{
+		{tag: "", wantName: "", wantOpts: ""},
+		{tag: "foo", wantName: "foo", wantOpts: ""},
+		{tag: "foo,optional", wantName: "foo", wantOpts: "optional"},
+		{tag: ",optional", wantName: "", wantOpts: "optional"},
+		{tag: "foo,a,b", wantName: "foo", wantOpts: "a,b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			name, opts := parseTag(tt.tag)
+			if name != tt.wantName {
+				t.Errorf("name = %q, want %q", name, tt.wantName)
+			}
+			if opts != tt.wantOpts {
+				t.Errorf("opts = %q, want %q", opts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestTagOptions_Contains(t *testing.T) {
+	opts := tagOptions("a,bc,d")
+	for _, name := range []string{"a", "bc", "d"} {
+		if !opts.Contains(name) {
+			t.Errorf("Contains(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"", "b", "c", "a,bc"} {
+		if opts.Contains(name) {
+			t.Errorf("Contains(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestTagOptions_Get(t *testing.T) {
+	tests := []struct {
+		opts   tagOptions
+		key    string
+		want   string
+		wantOK bool
+	}{
+		{opts: "", key: "desc", want: "", wantOK: false},
+		{opts: "desc=host", key: "desc", want: "host", wantOK: true},
+		{opts: "optional,desc=host", key: "desc", want: "host", wantOK: true},
+		{opts: "desc=", key: "desc", want: "", wantOK: true},
+		{opts: "desc='host, with port',optional", key: "desc", want: "host, with port", wantOK: true},
+		{opts: "description=host", key: "desc", want: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.opts), func(t *testing.T) {
+			got, ok := tt.opts.Get(tt.key)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Get(%q) = %q, %t, want %q, %t", tt.key, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+	if !tagOptions("desc='a,optional'").Contains("desc='a,optional'") {
+		t.Errorf("Contains() split a quoted value")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Foo", want: "foo"},
+		{in: "FooBar", want: "foo_bar"},
+		{in: "ClientID", want: "client_id"},
+		{in: "ID", want: "id"},
+		{in: "HTTPServer", want: "http_server"},
+		{in: "APIKeyID", want: "api_key_id"},
+		{in: "Auth0", want: "auth0"},
+		{in: "S3Bucket", want: "s3_bucket"},
+		{in: "already_snake", want: "already_snake"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := snakeCase(tt.in); got != tt.want {
+				t.Errorf("snakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TenantPlaceholder is replaced with the tenant ID in the prefix template of
+// a TenantStore.
+const TenantPlaceholder = "{tenant}"
+
+// A TenantStore reads the configs of the tenants of a multi-tenant service,
+// each from its own prefix. All tenants share a single ParamStore, so the
+// client, cache and concurrent reads are shared, instead of creating a
+// ParamStore for each tenant.
+type TenantStore struct {
+	ps       *ParamStore
+	template string
+
+	mu    sync.Mutex
+	names map[string][]string // Names read for each tenant
+}
+
+// NewTenantStore creates a TenantStore that reads the config of a tenant
+// under the prefix template, with TenantPlaceholder replaced by the tenant
+// ID:
+//
+//   ts, err := ssm.NewTenantStore("/tenants/{tenant}/app", ssm.WithCache(time.Minute))
+//
+// The options are used for the ParamStore shared by all tenants. The prefix
+// set with WithPrefix is not used. Options that depend on the prefix, such as
+// WithPathFetch, WithAdaptiveFetch and names passed to WithOnChange, use the
+// prefix of the tenant being read. Parameters cached with WithCache are cached
+// by their full name, so each tenant has its own cached values.
+func NewTenantStore(template string, opts ...Option) (*TenantStore, error) {
+	if !strings.Contains(template, TenantPlaceholder) {
+		return nil, fmt.Errorf("prefix template %q does not contain %s", template, TenantPlaceholder)
+	}
+	ps, err := NewParamStore(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TenantStore{
+		ps:       ps,
+		template: template,
+		names:    make(map[string][]string),
+	}, nil
+}
+
+// Read reads the config of the tenant into target, like ParamStore.Read.
+// The tenant ID may contain letters, numbers and the symbols . - _
+func (t *TenantStore) Read(ctx context.Context, tenant string, target interface{}) error {
+	prefix, err := t.prefix(tenant)
+	if err != nil {
+		return err
+	}
+	val, err := targetValue(target)
+	if err != nil {
+		return err
+	}
+	schema, _, err := t.ps.read(ctx, val, prefix)
+	if err != nil {
+		return err
+	}
+	t.remember(tenant, schema)
+	return nil
+}
+
+// ReadWithReport reads the config of the tenant into target like Read, and
+// reports where the value of each field came from, like
+// ParamStore.ReadWithReport. The Prefix of the fields is the prefix of the
+// tenant.
+func (t *TenantStore) ReadWithReport(ctx context.Context, tenant string, target interface{}) (*Report, error) {
+	prefix, err := t.prefix(tenant)
+	if err != nil {
+		return nil, err
+	}
+	val, err := targetValue(target)
+	if err != nil {
+		return nil, err
+	}
+	report, schema, err := t.ps.readWithReport(ctx, val, prefix)
+	if err != nil {
+		return nil, err
+	}
+	t.remember(tenant, schema)
+	return report, nil
+}
+
+// remember records the names in the schema as read for the tenant, to be
+// removed from the cache by Invalidate.
+func (t *TenantStore) remember(tenant string, schema map[string][][]int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.names[tenant] = without(t.names[tenant], sortedNames(schema))
+	t.names[tenant] = append(t.names[tenant], sortedNames(schema)...)
+}
+
+// Invalidate removes the parameters read for the tenant from the cache, so
+// they are read from SSM on the next read, for example when the tenant is
+// updated or removed.
+func (t *TenantStore) Invalidate(ctx context.Context, tenant string) {
+	t.mu.Lock()
+	names := t.names[tenant]
+	delete(t.names, tenant)
+	t.mu.Unlock()
+	t.ps.forget(ctx, names)
+}
+
+// prefix returns the prefix of the tenant.
+func (t *TenantStore) prefix(tenant string) (string, error) {
+	if tenant == "" {
+		return "", fmt.Errorf("tenant is required")
+	}
+	for _, r := range tenant {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '-', r == '_':
+		default:
+			return "", fmt.Errorf("invalid tenant %q", tenant)
+		}
+	}
+	prefix := strings.ReplaceAll(t.template, TenantPlaceholder, tenant)
+	return "/" + strings.Trim(prefix, "/"), nil
+}
@@ -0,0 +1,129 @@
+package ssm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTenantStore_Read(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/tenants/acme/app/host", "acme.example.com"))
+	mock.setParam(stringParam("/tenants/acme/app/port", "443"))
+	mock.setParam(stringParam("/tenants/globex/app/host", "globex.example.com"))
+	mock.setParam(stringParam("/tenants/globex/app/port", "8443"))
+	ts, err := NewTenantStore("tenants/{tenant}/app/", WithClient(mock), WithCache(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		Host string `ssm:"host"`
+		Port string `ssm:"port"`
+	}
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		var acme, globex config
+		if err := ts.Read(ctx, "acme", &acme); err != nil {
+			t.Fatal(err)
+		}
+		if err := ts.Read(ctx, "globex", &globex); err != nil {
+			t.Fatal(err)
+		}
+		if acme.Host != "acme.example.com" || acme.Port != "443" {
+			t.Errorf("acme = %+v", acme)
+		}
+		if globex.Host != "globex.example.com" || globex.Port != "8443" {
+			t.Errorf("globex = %+v", globex)
+		}
+	}
+	if mock.calls != 2 {
+		t.Errorf("GetParameters called %d times, want 2", mock.calls)
+	}
+
+	mock.setParam(stringParam("/tenants/acme/app/host", "new.example.com"))
+	ts.Invalidate(ctx, "acme")
+	var acme, globex config
+	if err := ts.Read(ctx, "acme", &acme); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Read(ctx, "globex", &globex); err != nil {
+		t.Fatal(err)
+	}
+	if acme.Host != "new.example.com" {
+		t.Errorf("acme.Host = %q, want %q", acme.Host, "new.example.com")
+	}
+	if mock.calls != 3 {
+		t.Errorf("GetParameters called %d times, want 3", mock.calls)
+	}
+}
+
+func TestTenantStore_errors(t *testing.T) {
+	if _, err := NewTenantStore("/tenants/app", WithClient(&mockSSM{})); err == nil {
+		t.Error("Want error for template without placeholder")
+	}
+
+	ts, err := NewTenantStore("/tenants/{tenant}", WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct {
+		Host string `ssm:"host"`
+	}
+	for _, tenant := range []string{"", "a/b", "a b"} {
+		if err := ts.Read(context.Background(), tenant, &cfg); err == nil {
+			t.Errorf("Read(%q) want error", tenant)
+		}
+	}
+	if err := ts.Read(context.Background(), "acme", &cfg); err == nil {
+		t.Error("Want error for missing parameter")
+	}
+}
+
+func TestTenantStore_prefixOptions(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/tenants/acme/app/host", "acme.example.com"))
+	mock.setParam(stringParam("/tenants/acme/app/port", "443"))
+	var changes []string
+	ts, err := NewTenantStore("/tenants/{tenant}/app",
+		WithClient(mock),
+		WithPathFetch(),
+		WithOnChange("host", func(old, new string) {
+			changes = append(changes, old+" -> "+new)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+		Port string `ssm:"port"`
+	}
+	ctx := context.Background()
+	report, err := ts.ReadWithReport(ctx, "acme", &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "acme.example.com" || cfg.Port != "443" {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	// Read with a single GetParametersByPath under the tenant prefix
+	if mock.pathCalls != 1 || mock.calls != 0 {
+		t.Errorf("Got %d path calls and %d calls, want 1 and 0", mock.pathCalls, mock.calls)
+	}
+	for _, f := range report.Fields {
+		if f.Prefix != "/tenants/acme/app" {
+			t.Errorf("%s: Prefix = %q, want %q", f.Field, f.Prefix, "/tenants/acme/app")
+		}
+	}
+
+	mock.setParam(stringParam("/tenants/acme/app/host", "new.example.com"))
+	if err := ts.Read(ctx, "acme", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"acme.example.com -> new.example.com"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("Changes = %v, want %v", changes, want)
+	}
+}
@@ -0,0 +1,47 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// fieldTTLs returns the cache TTLs set with the ttl= tag option on the fields
+// in the schema. Fields without the option use the TTL passed to WithCache or
+// WithSharedCache.
+//
+//   Token string `ssm:"token,ttl=30s"`
+//   Host  string `ssm:"host,ttl=6h"`
+func (s *ParamStore) fieldTTLs(t reflect.Type, schema map[string][][]int) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for name, indices := range schema {
+		for _, index := range indices {
+			f := structField(t, index)
+			tag, _ := s.lookupTag(f)
+			_, opts := parseTag(tag)
+			v, ok := opts.Get("ttl")
+			if !ok {
+				continue
+			}
+			ttl, err := time.ParseDuration(v)
+			if err != nil || ttl <= 0 {
+				return nil, fmt.Errorf("field %q: invalid ttl %q", f.Name, v)
+			}
+			// The shortest TTL applies if several fields read the
+			// parameter.
+			if prev, ok := ttls[name]; !ok || ttl < prev {
+				ttls[name] = ttl
+			}
+		}
+	}
+	return ttls, nil
+}
+
+// ttl returns the duration the parameter is cached for by the read of ctx.
+func (s *ParamStore) ttl(ctx context.Context, name string) time.Duration {
+	if ttl, ok := readOptionsFrom(ctx).ttls[name]; ok {
+		return ttl
+	}
+	return s.cacheTTL
+}
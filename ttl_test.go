@@ -0,0 +1,106 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_Read_ttl(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/token", "t1"),
+		stringParam("/host", "h1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.now = func() time.Time { return now }
+
+	var cfg struct {
+		Token string `ssm:"token,ttl=30s"`
+		Host  string `ssm:"host"`
+	}
+	read := func(wantToken, wantHost string) {
+		t.Helper()
+		if err := ps.Read(context.Background(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		check(t, cfg, []value{
+			{path: "Token", value: wantToken},
+			{path: "Host", value: wantHost},
+		})
+	}
+
+	read("t1", "h1")
+	mock.setParam(stringParam("/token", "t2"))
+	mock.setParam(stringParam("/host", "h2"))
+	read("t1", "h1")
+
+	// Only the token has expired
+	now = now.Add(30 * time.Second)
+	read("t2", "h1")
+
+	now = now.Add(time.Hour)
+	read("t2", "h2")
+}
+
+func TestParamStore_Read_invalidTTL(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}), WithCache(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []interface{}{
+		&struct {
+			A string `ssm:"a,ttl=soon"`
+		}{},
+		&struct {
+			A string `ssm:"a,ttl=-1s"`
+		}{},
+	} {
+		if err := ps.Read(context.Background(), target); err == nil {
+			t.Errorf("Read(%T) err = nil, want error", target)
+		}
+	}
+}
+
+func TestParamStore_Read_ttlPerRead(t *testing.T) {
+	now := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/token", "t1"),
+	}}
+	ps, err := NewParamStore(WithClient(mock), WithCache(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps.now = func() time.Time { return now }
+
+	var short struct {
+		Token string `ssm:"token,ttl=30s"`
+	}
+	if err := ps.Read(context.Background(), &short); err != nil {
+		t.Fatal(err)
+	}
+
+	// The TTL of the previous read does not apply to a read without the
+	// ttl= option.
+	now = now.Add(30 * time.Second)
+	var long struct {
+		Token string `ssm:"token"`
+	}
+	if err := ps.Read(context.Background(), &long); err != nil {
+		t.Fatal(err)
+	}
+	mock.setParam(stringParam("/token", "t2"))
+	now = now.Add(30 * time.Second)
+	if err := ps.Read(context.Background(), &long); err != nil {
+		t.Fatal(err)
+	}
+	check(t, long, []value{
+		{path: "Token", value: "t1"},
+	})
+}
@@ -0,0 +1,136 @@
+package ssm
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+// WithAdaptiveFetch tunes how parameters are fetched based on the latency and
+// throttling observed by previous reads, instead of WithPathFetch and
+// WithMaxConcurrency being tuned by hand for the shape of the tree:
+//
+// Reads with a prefix try both requesting the parameters by name with
+// GetParameters and reading everything under the prefix with
+// GetParametersByPath, after which the faster of the two is used. The slower
+// one is measured again every 20 reads, so changes in the tree are noticed.
+// Parameters not under the prefix are always requested by name.
+//
+// GetParameters requests are made concurrently, up to maxConcurrency at a
+// time, with the names split evenly between them. When a request is
+// throttled, the concurrency is halved, which fills the batches up to the
+// limit of 10 names. It increases again by one after each read that was not
+// throttled.
+//
+// WithAdaptiveFetch has no effect if WithPathFetch or WithResolver was passed.
+func WithAdaptiveFetch(maxConcurrency int) Option {
+	return func(s *ParamStore) {
+		if maxConcurrency < 1 {
+			maxConcurrency = 1
+		}
+		s.tuner = &tuner{max: maxConcurrency, concurrency: maxConcurrency}
+	}
+}
+
+// exploreEvery is the number of reads after which the slower fetch strategy
+// is measured again.
+const exploreEvery = 20
+
+// A tuner chooses the fetch strategy and concurrency of a ParamStore.
+type tuner struct {
+	max int
+
+	mu          sync.Mutex
+	reads       int
+	byName      latency
+	byPath      latency
+	concurrency int
+	throttled   bool // Since the last read
+}
+
+// latency is a moving average of the duration of reads.
+type latency struct {
+	avg time.Duration
+	n   int
+}
+
+func (l *latency) add(d time.Duration) {
+	if l.n == 0 {
+		l.avg = d
+	} else {
+		l.avg = (7*l.avg + 3*d) / 10
+	}
+	l.n++
+}
+
+// usePath reports whether the next read should use GetParametersByPath.
+func (t *tuner) usePath() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reads++
+	switch {
+	case t.byName.n == 0:
+		return false
+	case t.byPath.n == 0:
+		return true
+	case t.reads%exploreEvery == 0:
+		return t.byPath.avg >= t.byName.avg
+	}
+	return t.byPath.avg < t.byName.avg
+}
+
+// observe records the duration of a successful read.
+func (t *tuner) observe(path bool, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if path {
+		t.byPath.add(d)
+	} else {
+		t.byName.add(d)
+	}
+	if !t.throttled && t.concurrency < t.max {
+		t.concurrency++
+	}
+	t.throttled = false
+}
+
+// throttle records that a request was throttled.
+func (t *tuner) throttle() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.throttled = true
+	if t.concurrency > 1 {
+		t.concurrency /= 2
+	}
+}
+
+// batching returns the number of requests to make at the same time, and the
+// number of names to request in each to fetch n names.
+func (t *tuner) batching(n int) (concurrency, size int) {
+	t.mu.Lock()
+	concurrency = t.concurrency
+	t.mu.Unlock()
+	size = (n + concurrency - 1) / concurrency
+	if size < 1 {
+		size = 1
+	}
+	if size > maxNames {
+		size = maxNames
+	}
+	return concurrency, size
+}
+
+// throttleCodes are the error codes of requests that were throttled.
+var throttleCodes = map[string]bool{
+	"ThrottlingException":  true,
+	"TooManyRequests":      true,
+	"RequestLimitExceeded": true,
+}
+
+// isThrottle reports whether err is caused by throttling.
+func isThrottle(err error) bool {
+	var aerr smithy.APIError
+	return errors.As(err, &aerr) && throttleCodes[aerr.ErrorCode()]
+}
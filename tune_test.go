@@ -0,0 +1,108 @@
+package ssm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_Read_adaptiveStrategy(t *testing.T) {
+	mock := &mockSSM{
+		params: []types.Parameter{
+			stringParam("/app/host", "a"),
+			stringParam("/app/port", "1"),
+			stringParam("/app/user", "b"),
+		},
+		// Only GetParameters is delayed, so reading by path is faster.
+		delay: 20 * time.Millisecond,
+	}
+	ps, err := NewParamStore(WithClient(mock), WithPrefix("app"), WithAdaptiveFetch(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+		Port string `ssm:"port"`
+		User string `ssm:"user"`
+	}
+	read := func() (calls, pathCalls int) {
+		t.Helper()
+		mock.mu.Lock()
+		mock.calls, mock.pathCalls = 0, 0
+		mock.mu.Unlock()
+		if err := ps.Read(context.Background(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+		return mock.calls, mock.pathCalls
+	}
+
+	if calls, pathCalls := read(); calls == 0 || pathCalls > 0 {
+		t.Errorf("First read: %d GetParameters, %d GetParametersByPath calls, want by name", calls, pathCalls)
+	}
+	if calls, pathCalls := read(); calls > 0 || pathCalls == 0 {
+		t.Errorf("Second read: %d GetParameters, %d GetParametersByPath calls, want by path", calls, pathCalls)
+	}
+	for i := 3; i < exploreEvery; i++ {
+		if calls, _ := read(); calls > 0 {
+			t.Fatalf("Read %d: %d GetParameters calls, want by path", i, calls)
+		}
+	}
+	if calls, pathCalls := read(); calls == 0 || pathCalls > 0 {
+		t.Errorf("Read %d: %d GetParameters, %d GetParametersByPath calls, want by name to be measured again", exploreEvery, calls, pathCalls)
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "a"},
+		{path: "Port", value: "1"},
+		{path: "User", value: "b"},
+	})
+}
+
+func TestParamStore_Read_adaptiveBatching(t *testing.T) {
+	ty, params, want := largeConfig(12)
+	mock := &mockSSM{params: params}
+	ps, err := NewParamStore(WithClient(mock), WithRetry(3, time.Millisecond), WithAdaptiveFetch(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	val := reflect.New(ty)
+	if err := ps.Read(context.Background(), val.Interface()); err != nil {
+		t.Fatal(err)
+	}
+	check(t, val.Elem().Interface(), want)
+	// 12 names split evenly over 4 requests
+	if mock.calls != 4 {
+		t.Errorf("GetParameters called %d times, want 4", mock.calls)
+	}
+
+	mock.mu.Lock()
+	mock.throttle = 1
+	mock.calls = 0
+	mock.mu.Unlock()
+	if err := ps.Read(context.Background(), reflect.New(ty).Interface()); err != nil {
+		t.Fatal(err)
+	}
+	if got := ps.tuner.concurrency; got != 2 {
+		t.Errorf("Concurrency after throttling = %d, want 2", got)
+	}
+
+	mock.mu.Lock()
+	mock.calls = 0
+	mock.mu.Unlock()
+	if err := ps.Read(context.Background(), reflect.New(ty).Interface()); err != nil {
+		t.Fatal(err)
+	}
+	// 6 names per request at a concurrency of 2
+	if mock.calls != 2 {
+		t.Errorf("GetParameters called %d times, want 2", mock.calls)
+	}
+	if got := ps.tuner.concurrency; got != 3 {
+		t.Errorf("Concurrency after read without throttling = %d, want 3", got)
+	}
+}
@@ -0,0 +1,63 @@
+package ssm
+
+import (
+	"runtime/debug"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+const modulePath = "github.com/akupila/ssm"
+
+// userAgentKey is added to the User-Agent of requests to SSM, followed by the
+// version of this module, so the requests can be attributed in CloudTrail.
+const userAgentKey = "akupila-ssm"
+
+// moduleVersion is the version of this module the program was built with.
+var moduleVersion = readModuleVersion()
+
+func readModuleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil || dep.Version == "" {
+			return "devel"
+		}
+		return dep.Version
+	}
+	// Built from this module, such as in tests
+	return "devel"
+}
+
+// WithAppName identifies the application in the User-Agent of requests to
+// SSM, which is recorded in CloudTrail:
+//
+//   WithAppName("billing-api")
+//
+// Requests always include akupila-ssm and the version of this package.
+func WithAppName(name string) Option {
+	return func(s *ParamStore) {
+		s.appName = name
+	}
+}
+
+// clientOptions returns the options to pass to each call of the Client.
+func (s *ParamStore) clientOptions() []func(*ssm.Options) {
+	return []func(*ssm.Options){func(o *ssm.Options) {
+		o.APIOptions = append(o.APIOptions, awsmiddleware.AddUserAgentKeyValue(userAgentKey, moduleVersion))
+		o.APIOptions = append(o.APIOptions, s.apiOptions...)
+		if s.appName != "" {
+			o.AppID = s.appName
+		}
+		if s.maxAttempts > 0 {
+			// Retried by send instead
+			o.Retryer = aws.NopRetryer{}
+		}
+	}}
+}
@@ -0,0 +1,101 @@
+package ssm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A specific version of a parameter is read by adding the version to the name
+// with the version= tag option, or directly in the name:
+//
+//   Host string `ssm:"host,version=3"`
+//   Port string `ssm:"port:2"`
+//
+// Pinned parameters are read as name:version, so they do not change if a new
+// version is written. Other fields reading the same parameter without a
+// version read the latest version.
+//
+// The version with a label is read in the same way with the label= tag
+// option, or WithLabel for every parameter:
+//
+//   Host string `ssm:"host,label=prod-approved"`
+
+// WithLabel reads the version of every parameter that has the label, such as
+// the version approved for production. Fields with the version= or label= tag
+// option, or a version in the name, read that version instead. Map fields
+// read the latest version.
+func WithLabel(label string) Option {
+	return func(s *ParamStore) {
+		s.label = label
+	}
+}
+
+// selectVersion adds the version set with the version= or label= tag option,
+// or the label set with WithLabel, to the name. single reports whether the
+// field is read from a single parameter, as only those can select a version.
+func (s *ParamStore) selectVersion(name string, opts tagOptions, single bool) (string, error) {
+	version, hasVersion := opts.Get("version")
+	label, hasLabel := opts.Get("label")
+	_, selector := splitSelector(name)
+	switch {
+	case hasVersion && hasLabel:
+		return "", fmt.Errorf("version and label both set")
+	case (hasVersion || hasLabel) && selector != "":
+		return "", fmt.Errorf("version set twice")
+	case !single && (hasVersion || hasLabel || selector != ""):
+		return "", fmt.Errorf("version requires a single parameter")
+	case hasVersion:
+		if n, err := strconv.ParseInt(version, 10, 64); err != nil || n < 1 {
+			return "", fmt.Errorf("invalid version %q", version)
+		}
+		return name + ":" + version, nil
+	case hasLabel:
+		if !validLabel(label) {
+			return "", fmt.Errorf("invalid label %q", label)
+		}
+		return name + ":" + label, nil
+	case single && selector == "" && s.label != "":
+		return name + ":" + s.label, nil
+	}
+	return name, nil
+}
+
+// validLabel reports whether the label can be used as a selector. Labels
+// starting with a digit would be read as a version.
+func validLabel(label string) bool {
+	if label == "" || label[0] >= '0' && label[0] <= '9' {
+		return false
+	}
+	return !strings.ContainsAny(label, ":/")
+}
+
+// splitSelector splits the version or label selector, such as :3, from the
+// name of a parameter. Parameter names cannot contain colons, so a colon in
+// the last element of the name starts the selector.
+func splitSelector(name string) (string, string) {
+	last := strings.LastIndex(name, "/")
+	i := strings.LastIndex(name, ":")
+	if i <= last {
+		return name, ""
+	}
+	return name[:i], name[i:]
+}
+
+// matchSelectors adds the selector to the name of parameters that were
+// requested with a selector, so they can be matched with the schema. SSM
+// returns the name without the selector, with the selector in a separate
+// field.
+func matchSelectors(params []types.Parameter) []types.Parameter {
+	for i, p := range params {
+		selector := aws.ToString(p.Selector)
+		if selector == "" || strings.HasSuffix(aws.ToString(p.Name), selector) {
+			continue
+		}
+		params[i].Name = aws.String(aws.ToString(p.Name) + selector)
+	}
+	return params
+}
@@ -0,0 +1,309 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// DefaultPollInterval is the interval at which parameters are polled for
+// changes if WithPollInterval was not passed.
+const DefaultPollInterval = time.Minute
+
+// WithPollInterval sets the interval at which parameters are polled for
+// changes. Defaults to DefaultPollInterval.
+func WithPollInterval(d time.Duration) Option {
+	return func(s *ParamStore) {
+		s.pollInterval = d
+	}
+}
+
+// SubscribeValue polls a single parameter for changes. The current value is
+// sent on the returned channel, followed by each new value when the parameter
+// changes. The name is resolved in the same way as in a struct tag of the
+// target passed to Read: it is relative to the prefix unless it starts with /
+// or is an ARN.
+//
+// An error is returned if the parameter cannot be read initially. Errors
+// while polling are logged and polling continues. The channel is closed when
+// ctx is done.
+func (s *ParamStore) SubscribeValue(ctx context.Context, name string) (<-chan string, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	name = tagName(s.prefix, strings.TrimPrefix(name, "~/"))
+	current, err := s.poll(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		t := time.NewTicker(s.pollInterval)
+		defer t.Stop()
+
+		value := current
+		for {
+			select {
+			case ch <- aws.ToString(value.Value):
+			case <-ctx.Done():
+				return
+			}
+			for {
+				select {
+				case <-t.C:
+				case <-ctx.Done():
+					return
+				}
+				p, err := s.poll(ctx, name)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					s.log(ctx, slog.LevelWarn, "poll parameter",
+						slog.String("name", name),
+						slog.String("error", err.Error()),
+					)
+					continue
+				}
+				if changed(value, p) {
+					value = p
+					break
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// poll gets the current value of a single parameter from SSM.
+func (s *ParamStore) poll(ctx context.Context, name string) (types.Parameter, error) {
+	params, err := s.fetch(ctx, []string{name})
+	if err != nil {
+		return types.Parameter{}, fmt.Errorf("read ssm: %v", err)
+	}
+	if len(params) == 0 {
+		return types.Parameter{}, NotFoundError{names: []string{name}}
+	}
+	return params[0], nil
+}
+
+// changed reports whether the parameter has changed. The version is compared
+// if both parameters have one, otherwise the value.
+func changed(old, new types.Parameter) bool {
+	if old.Version != 0 && new.Version != 0 {
+		return old.Version != new.Version
+	}
+	return aws.ToString(old.Value) != aws.ToString(new.Value)
+}
+
+// An Update is sent by Updates when parameters change.
+type Update struct {
+	// Config is a pointer to a new value of the target type, with the current
+	// values of all parameters. Fields not read from SSM are not set.
+	Config interface{}
+	// Changed holds the paths of the fields that changed, such as
+	// "DB.Password".
+	Changed []string
+}
+
+// Updates reads the parameters into target and then polls them for changes.
+// When one or more parameters change, an Update with a new copy of the config
+// is sent on the returned channel. target is not modified after the initial
+// read.
+//
+// An error is returned if the initial read fails. Errors while polling are
+// logged and polling continues. The channel is closed when ctx is done.
+func (s *ParamStore) Updates(ctx context.Context, target interface{}) (<-chan Update, error) {
+	ctx, ty, schema, params, err := s.readInitial(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	names := sortedNames(schema)
+
+	ch := make(chan Update)
+	go func() {
+		defer close(ch)
+		t := time.NewTicker(s.pollInterval)
+		defer t.Stop()
+
+		current := byName(params)
+		for {
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return
+			}
+
+			params, err := s.fetch(ctx, names)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.log(ctx, slog.LevelWarn, "poll parameters", slog.String("error", err.Error()))
+				continue
+			}
+			latest := byName(params)
+			changedNames := diff(names, current, latest)
+			if len(changedNames) == 0 {
+				continue
+			}
+
+			snapshot := reflect.New(ty)
+			if err := s.assign(ctx, snapshot.Elem(), schema, params); err != nil {
+				s.log(ctx, slog.LevelWarn, "poll parameters", slog.String("error", err.Error()))
+				continue
+			}
+			current = latest
+
+			u := Update{Config: snapshot.Interface()}
+			for _, name := range changedNames {
+				for _, index := range schema[name] {
+					u.Changed = append(u.Changed, strings.Join(fieldPath(ty, index), "."))
+				}
+			}
+			select {
+			case ch <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// A Change is sent by Watch when a parameter changes.
+type Change struct {
+	// Name is the name of the parameter.
+	Name string
+	// Field is the path of the field the parameter is read into, such as
+	// "DB.Password".
+	Field string
+	// OldValue and NewValue are the values before and after the change. If
+	// the parameter was deleted, NewValue is empty.
+	OldValue string
+	NewValue string
+	// Version is the version of the parameter after the change, or 0 if the
+	// parameter was deleted.
+	Version int64
+}
+
+// Watch reads the parameters into target and then polls them for changes.
+// A Change is sent on the returned channel for each field whose parameter
+// changes, for example to reconnect to a database when its password is
+// rotated. target is not modified after the initial read.
+//
+// Values of SecureString parameters are included in the Change, so they must
+// not be logged.
+//
+// An error is returned if the initial read fails. Errors while polling are
+// logged and polling continues. The channel is closed when ctx is done.
+func (s *ParamStore) Watch(ctx context.Context, target interface{}) (<-chan Change, error) {
+	ctx, ty, schema, params, err := s.readInitial(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	names := sortedNames(schema)
+
+	ch := make(chan Change)
+	go func() {
+		defer close(ch)
+		t := time.NewTicker(s.pollInterval)
+		defer t.Stop()
+
+		current := byName(params)
+		for {
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return
+			}
+
+			params, err := s.fetch(ctx, names)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.log(ctx, slog.LevelWarn, "poll parameters", slog.String("error", err.Error()))
+				continue
+			}
+			latest := byName(params)
+			for _, name := range diff(names, current, latest) {
+				prev, next := current[name], latest[name]
+				for _, index := range schema[name] {
+					c := Change{
+						Name:     name,
+						Field:    strings.Join(fieldPath(ty, index), "."),
+						OldValue: aws.ToString(prev.Value),
+						NewValue: aws.ToString(next.Value),
+						Version:  next.Version,
+					}
+					select {
+					case ch <- c:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			current = latest
+		}
+	}()
+	return ch, nil
+}
+
+// readInitial reads the parameters into target like Read, returning the type
+// of the struct, its schema and the parameters read. The returned context
+// carries the options set with tag options on the fields of target, such as
+// nodecrypt, to poll the parameters with.
+func (s *ParamStore) readInitial(ctx context.Context, target interface{}) (context.Context, reflect.Type, map[string][][]int, []types.Parameter, error) {
+	val, err := targetValue(target)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	schema, params, err := s.read(ctx, val, s.prefix)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	ctx, err = s.withReadOptions(ctx, val.Type(), s.prefix, schema)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return ctx, val.Type(), schema, params, nil
+}
+
+// diff returns the names of the parameters that were added, removed or
+// changed between current and latest. The path of a map field is returned if
+// any parameter under it changed.
+func diff(names []string, current, latest map[string]types.Parameter) []string {
+	var out []string
+	for _, name := range names {
+		if isPath(name) {
+			if len(diff(sortedKeys(children(name, current), children(name, latest)), current, latest)) > 0 {
+				out = append(out, name)
+			}
+			continue
+		}
+		prev, hadPrev := current[name]
+		next, hasNext := latest[name]
+		if hadPrev != hasNext || (hasNext && changed(prev, next)) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// byName returns the parameters by name.
+func byName(params []types.Parameter) map[string]types.Parameter {
+	m := make(map[string]types.Parameter, len(params))
+	for _, p := range params {
+		m[*p.Name] = p
+	}
+	return m
+}
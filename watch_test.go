@@ -0,0 +1,318 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParamStore_SubscribeValue(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/dev/kill_switch", "off"))
+	mock.setParam(stringParam("/dev/other", "foo"))
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPrefix("dev"),
+		WithPollInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := ps.SubscribeValue(ctx, "kill_switch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := receive(t, ch); got != "off" {
+		t.Errorf("Initial value = %q, want %q", got, "off")
+	}
+
+	mock.setParam(stringParam("/dev/other", "bar"))
+	mock.setParam(stringParam("/dev/kill_switch", "on"))
+	if got := receive(t, ch); got != "on" {
+		t.Errorf("Value = %q, want %q", got, "on")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Channel not closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for channel to close")
+	}
+}
+
+func TestParamStore_SubscribeValue_names(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/dev/a", "prefixed"))
+	mock.setParam(stringParam("/shared/b", "absolute"))
+	mock.setParam(stringParam("/c", "unprefixed"))
+
+	tests := []struct {
+		prefix string
+		name   string
+		want   string
+	}{
+		{prefix: "dev", name: "a", want: "prefixed"},
+		{prefix: "dev", name: "~/a", want: "prefixed"},
+		{prefix: "dev", name: "/shared/b", want: "absolute"},
+		{prefix: "", name: "c", want: "unprefixed"},
+		{prefix: "", name: "/shared/b/", want: "absolute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix+"_"+tt.name, func(t *testing.T) {
+			ps, err := NewParamStore(WithClient(mock), WithPrefix(tt.prefix))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ch, err := ps.SubscribeValue(ctx, tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := receive(t, ch); got != tt.want {
+				t.Errorf("Value = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamStore_SubscribeValue_notFound(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ps.SubscribeValue(context.Background(), "kill_switch")
+	if _, ok := err.(NotFoundError); !ok {
+		t.Errorf("SubscribeValue() err = %v, want NotFoundError", err)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	v1 := stringParam("/foo", "a")
+	v1.Version = 1
+	v2 := stringParam("/foo", "a")
+	v2.Version = 2
+
+	tests := []struct {
+		name     string
+		old, new types.Parameter
+		want     bool
+	}{
+		{name: "SameVersion", old: v1, new: v1, want: false},
+		{name: "NewVersion", old: v1, new: v2, want: true},
+		{name: "SameValue", old: stringParam("/foo", "a"), new: stringParam("/foo", "a"), want: false},
+		{name: "NewValue", old: stringParam("/foo", "a"), new: stringParam("/foo", "b"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := changed(tt.old, tt.new); got != tt.want {
+				t.Errorf("changed() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func receive(t *testing.T, ch <-chan string) string {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for value")
+		return ""
+	}
+}
+
+func TestParamStore_Updates(t *testing.T) {
+	type config struct {
+		Host string `ssm:"host"`
+		DB   struct {
+			User string `ssm:"user"`
+			Pass string `ssm:"pass"`
+		} `ssm:"db"`
+	}
+
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/host", "localhost"))
+	mock.setParam(stringParam("/db/user", "alice"))
+	mock.setParam(secureStringParam("/db/pass", "secret"))
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPollInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var cfg config
+	ch, err := ps.Updates(ctx, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "localhost"},
+		{path: "DB.User", value: "alice"},
+		{path: "DB.Pass", value: "secret"},
+	})
+
+	mock.setParam(secureStringParam("/db/pass", "rotated"))
+	var u Update
+	select {
+	case u = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for update")
+	}
+	if diff := cmp.Diff(u.Changed, []string{"DB.Pass"}); diff != "" {
+		t.Errorf("Changed (-got +want)\n%s", diff)
+	}
+	got, ok := u.Config.(*config)
+	if !ok {
+		t.Fatalf("Config is %T, want *config", u.Config)
+	}
+	check(t, *got, []value{
+		{path: "Host", value: "localhost"},
+		{path: "DB.User", value: "alice"},
+		{path: "DB.Pass", value: "rotated"},
+	})
+	// Target is not modified
+	check(t, cfg, []value{{path: "DB.Pass", value: "secret"}})
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestParamStore_Updates_initialError(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+	}
+	if _, err := ps.Updates(context.Background(), &cfg); err == nil {
+		t.Error("Want error")
+	}
+}
+
+func TestParamStore_Watch(t *testing.T) {
+	type config struct {
+		Host string `ssm:"host"`
+		DB   struct {
+			Pass string `ssm:"pass"`
+		} `ssm:"db"`
+	}
+
+	mock := &mockSSM{}
+	mock.setParam(stringParam("/host", "localhost"))
+	mock.setParam(secureStringParam("/db/pass", "secret"))
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPollInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var cfg config
+	ch, err := ps.Watch(ctx, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "localhost"},
+		{path: "DB.Pass", value: "secret"},
+	})
+
+	mock.setParam(secureStringParam("/db/pass", "rotated"))
+	var c Change
+	select {
+	case c = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for change")
+	}
+	want := Change{
+		Name:     "/db/pass",
+		Field:    "DB.Pass",
+		OldValue: "secret",
+		NewValue: "rotated",
+		Version:  2,
+	}
+	if diff := cmp.Diff(c, want); diff != "" {
+		t.Errorf("Change (-got +want)\n%s", diff)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestParamStore_Watch_noDecrypt(t *testing.T) {
+	mock := &mockSSM{}
+	mock.setParam(secureStringParam("/token", "secret"))
+	ps, err := NewParamStore(
+		WithClient(mock),
+		WithPollInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var cfg struct {
+		Token string `ssm:"token,nodecrypt"`
+	}
+	ch, err := ps.Watch(ctx, &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "Token", value: "<ENCRYPTED>"},
+	})
+
+	mock.setParam(secureStringParam("/token", "rotated"))
+	var c Change
+	select {
+	case c = <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for change")
+	}
+	if c.OldValue != "<ENCRYPTED>" || c.NewValue != "<ENCRYPTED>" {
+		t.Errorf("Change = %+v, want encrypted values", c)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestParamStore_Watch_initialError(t *testing.T) {
+	ps, err := NewParamStore(WithClient(&mockSSM{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+	}
+	if _, err := ps.Watch(context.Background(), &cfg); err == nil {
+		t.Error("Want error")
+	}
+}
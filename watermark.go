@@ -0,0 +1,123 @@
+package ssm
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A Watermark identifies a secret value read by a process, without
+// containing the value. If a secret is found where it should not be, such as
+// in a log, the watermarks recorded by each process can be matched against it
+// to find out which process and read it came from.
+type Watermark struct {
+	// Name is the name of the parameter.
+	Name string `json:"name"`
+	// Version is the version of the parameter read.
+	Version int64 `json:"version"`
+	// ReadID is a random ID of the read, shared by the watermarks of all
+	// parameters read at the same time.
+	ReadID string `json:"read_id"`
+	// KeyID identifies the key of the process, which is random and the same
+	// for every watermark recorded by the process. It is derived from the
+	// key with a hash and does not reveal it.
+	KeyID string `json:"key_id"`
+	// MAC is the hex encoded HMAC-SHA256 of the value, keyed with the key of
+	// the process.
+	MAC string `json:"mac"`
+}
+
+// Matches reports whether value is the value the watermark was recorded for.
+// The key never leaves the process, so Matches only reports true in the
+// process that recorded the watermark. A leaked value is traced by passing it
+// to the processes with the KeyIDs of its watermarks, for example through an
+// internal endpoint.
+func (w Watermark) Matches(value string) bool {
+	key := processKey()
+	if w.KeyID != watermarkKeyID(key) {
+		return false
+	}
+	mac, err := hex.DecodeString(w.MAC)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(mac, watermarkMAC(key, value))
+}
+
+// WithWatermarks calls record with a Watermark of each SecureString parameter
+// read, including refreshes by StartRefresh that find a change. The
+// watermarks are meant to be stored, for example in a log, so leaked secrets
+// can be correlated with the process and read that produced them:
+//
+//   ssm.WithWatermarks(func(w ssm.Watermark) {
+//       logger.Info("read secret", "name", w.Name, "read_id", w.ReadID, "key_id", w.KeyID, "mac", w.MAC)
+//   })
+//
+// The values are never stored. Each process uses its own random key, which is
+// kept in memory, so the watermarks of a value differ between processes and
+// the stored watermarks cannot be used to guess the values.
+func WithWatermarks(record func(w Watermark)) Option {
+	return func(s *ParamStore) {
+		s.watermark = record
+	}
+}
+
+// processKey returns the watermark key of the process, which is generated
+// once.
+var processKey = sync.OnceValue(func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic("ssm: generate watermark key: " + err.Error())
+	}
+	return key
+})
+
+// recordWatermarks calls the watermark callback of s, if set, for the
+// SecureString parameters in params.
+func (s *ParamStore) recordWatermarks(ctx context.Context, params []types.Parameter) {
+	if s.watermark == nil {
+		return
+	}
+	var (
+		readID string
+		key    = processKey()
+	)
+	for _, p := range params {
+		if p.Type != types.ParameterTypeSecureString || p.Value == nil || !s.decrypts(ctx, *p.Name) {
+			continue
+		}
+		if readID == "" {
+			id := make([]byte, 8)
+			if _, err := rand.Read(id); err != nil {
+				return
+			}
+			readID = hex.EncodeToString(id)
+		}
+		s.watermark(Watermark{
+			Name:    aws.ToString(p.Name),
+			Version: p.Version,
+			ReadID:  readID,
+			KeyID:   watermarkKeyID(key),
+			MAC:     hex.EncodeToString(watermarkMAC(key, *p.Value)),
+		})
+	}
+}
+
+// watermarkKeyID returns the ID of the key, the first 8 bytes of its SHA-256
+// hash.
+func watermarkKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func watermarkMAC(key []byte, value string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(value))
+	return h.Sum(nil)
+}
@@ -0,0 +1,93 @@
+package ssm
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestParamStore_Read_watermarks(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		secureStringParam("/db/password", "hunter2"),
+		secureStringParam("/api_key", "key-123"),
+		secureStringParam("/token", "encrypted"),
+		stringParam("/host", "db.example.com"),
+	}}
+	var (
+		mu    sync.Mutex
+		marks []Watermark
+	)
+	ps, err := NewParamStore(WithClient(mock), WithWatermarks(func(w Watermark) {
+		mu.Lock()
+		defer mu.Unlock()
+		marks = append(marks, w)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Password string `ssm:"db/password"`
+		APIKey   string `ssm:"api_key"`
+		Token    string `ssm:"token,nodecrypt"`
+		Host     string `ssm:"host"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(marks) != 2 {
+		t.Fatalf("Got %d watermarks, want 2: %+v", len(marks), marks)
+	}
+	byName := make(map[string]Watermark)
+	for _, w := range marks {
+		byName[w.Name] = w
+		if strings.Contains(w.MAC, "hunter2") || strings.Contains(w.MAC, "key-123") {
+			t.Errorf("Watermark %s contains the value", w.Name)
+		}
+	}
+	pass := byName["/db/password"]
+	if !pass.Matches("hunter2") {
+		t.Error("Watermark of /db/password does not match its value")
+	}
+	if pass.Matches("key-123") {
+		t.Error("Watermark of /db/password matches another value")
+	}
+	if pass.ReadID == "" || pass.ReadID != byName["/api_key"].ReadID {
+		t.Errorf("ReadIDs = %q, %q, want the same ID", pass.ReadID, byName["/api_key"].ReadID)
+	}
+	if pass.KeyID == "" || pass.KeyID != byName["/api_key"].KeyID {
+		t.Error("Want the same key ID for every watermark of the process")
+	}
+	if strings.Contains(pass.KeyID, hex.EncodeToString(processKey())[:16]) {
+		t.Error("Key ID contains the key")
+	}
+
+	marks = nil
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(marks) != 2 || marks[0].ReadID == pass.ReadID {
+		t.Errorf("Want a new read ID for each read")
+	}
+}
+
+func TestWatermark_Matches_invalid(t *testing.T) {
+	key := watermarkKeyID(processKey())
+	mac := hex.EncodeToString(watermarkMAC(processKey(), "hunter2"))
+	for _, w := range []Watermark{
+		{KeyID: "0123456789abcdef", MAC: mac},
+		{KeyID: key, MAC: "not hex"},
+	} {
+		if w.Matches("hunter2") {
+			t.Errorf("%+v matches, want no match", w)
+		}
+	}
+	if !(Watermark{KeyID: key, MAC: mac}).Matches("hunter2") {
+		t.Error("Want a match with the key of the process")
+	}
+}
@@ -0,0 +1,164 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// A WriteOption sets an option for writing parameters.
+type WriteOption func(o *writeOptions)
+
+type writeOptions struct {
+	overwrite bool
+	secure    bool
+	keyID     string
+	tags      map[string]string
+}
+
+// WithOverwrite replaces the values of existing parameters. By default, writing
+// a parameter that already exists fails.
+func WithOverwrite() WriteOption {
+	return func(o *writeOptions) {
+		o.overwrite = true
+	}
+}
+
+// WithSecureString writes the parameters as SecureString, encrypted with the
+// KMS key keyID. If keyID is empty, the default key for the account is used.
+func WithSecureString(keyID string) WriteOption {
+	return func(o *writeOptions) {
+		o.secure = true
+		o.keyID = keyID
+	}
+}
+
+// WithTags adds AWS resource tags to the parameters written, for example to
+// attribute cost and ownership:
+//
+//   WithTags(map[string]string{"team": "payments", "environment": "prod"})
+//
+// Tags are added to existing parameters when they are overwritten. Tags not
+// in the map are left unchanged.
+func WithTags(tags map[string]string) WriteOption {
+	return func(o *writeOptions) {
+		o.tags = tags
+	}
+}
+
+// WriteMap writes the values in the map as String parameters under prefix.
+// Keys are relative to the prefix and may contain / to create nested
+// parameters:
+//
+//   ps.WriteMap(ctx, "dev", map[string]string{
+//       "host":    "localhost", // /dev/host
+//       "db/user": "alice",     // /dev/db/user
+//   })
+//
+// The parameters are written one at a time, in order of their names. Writing
+// stops at the first error; parameters written before it are not removed.
+func (s *ParamStore) WriteMap(ctx context.Context, prefix string, values map[string]string, opts ...WriteOption) error {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	prefix = "/" + strings.Trim(prefix, "/")
+	if prefix == "/" {
+		prefix = ""
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = prefix + "/" + strings.Trim(k, "/")
+	}
+	if err := s.checkNames(names); err != nil {
+		return err
+	}
+	for i, k := range keys {
+		name := names[i]
+		typ := types.ParameterTypeString
+		if o.secure {
+			typ = types.ParameterTypeSecureString
+		}
+		if err := s.put(ctx, name, values[k], typ, o); err != nil {
+			return fmt.Errorf("write %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// put writes a single parameter of type typ. The KMS key in o is used for
+// SecureString parameters. The parameter is removed from the caches, so the
+// new value is read on the next Read.
+func (s *ParamStore) put(ctx context.Context, name, value string, typ types.ParameterType, o writeOptions) error {
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(name),
+		Value:     aws.String(value),
+		Type:      typ,
+		Overwrite: aws.Bool(o.overwrite),
+	}
+	if typ == types.ParameterTypeSecureString && o.keyID != "" {
+		input.KeyId = aws.String(o.keyID)
+	}
+	tags := awsTags(o.tags)
+	if !o.overwrite {
+		// Tags can only be passed when creating a parameter.
+		input.Tags = tags
+	}
+	var resp *ssm.PutParameterOutput
+	err := s.send(ctx, func() (err error) {
+		resp, err = s.cli.PutParameter(ctx, input, s.clientOptions()...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if o.overwrite && len(tags) > 0 {
+		input := &ssm.AddTagsToResourceInput{
+			ResourceType: types.ResourceTypeForTaggingParameter,
+			ResourceId:   aws.String(name),
+			Tags:         tags,
+		}
+		err := s.send(ctx, func() error {
+			_, err := s.cli.AddTagsToResource(ctx, input, s.clientOptions()...)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("add tags: %v", err)
+		}
+	}
+	s.forget(ctx, []string{name})
+	s.log(ctx, slog.LevelDebug, "wrote parameter",
+		slog.String("name", name),
+		slog.Int64("version", resp.Version),
+	)
+	return nil
+}
+
+// awsTags converts tags to SSM tags, sorted by key.
+func awsTags(tags map[string]string) []types.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]types.Tag, len(keys))
+	for i, k := range keys {
+		out[i] = types.Tag{Key: aws.String(k), Value: aws.String(tags[k])}
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParamStore_WriteMap(t *testing.T) {
+	mock := &mockSSM{}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]string{
+		"host":     "localhost",
+		"/db/user": "alice",
+	}
+	if err := ps.WriteMap(context.Background(), "dev/", values); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+		DB   struct {
+			User string `ssm:"user"`
+		} `ssm:"db"`
+	}
+	WithPrefix("dev")(ps)
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	check(t, cfg, []value{
+		{path: "Host", value: "localhost"},
+		{path: "DB.User", value: "alice"},
+	})
+	if mock.puts[0].Type != types.ParameterTypeString {
+		t.Errorf("Type = %s, want String", mock.puts[0].Type)
+	}
+}
+
+func TestParamStore_WriteMap_secure(t *testing.T) {
+	mock := &mockSSM{}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]string{"token": "secret"}
+	if err := ps.WriteMap(context.Background(), "", values, WithSecureString("alias/config")); err != nil {
+		t.Fatal(err)
+	}
+	put := mock.puts[0]
+	if *put.Name != "/token" {
+		t.Errorf("Name = %s, want /token", *put.Name)
+	}
+	if put.Type != types.ParameterTypeSecureString {
+		t.Errorf("Type = %s, want SecureString", put.Type)
+	}
+	if aws.ToString(put.KeyId) != "alias/config" {
+		t.Errorf("KeyId = %s, want alias/config", aws.ToString(put.KeyId))
+	}
+}
+
+func TestParamStore_WriteMap_overwrite(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/dev/host", "localhost"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]string{"host": "example.com"}
+	err = ps.WriteMap(context.Background(), "dev", values)
+	if err == nil {
+		t.Fatal("Want error without WithOverwrite")
+	}
+	t.Logf("Got expected error: %v", err)
+
+	if err := ps.WriteMap(context.Background(), "dev", values, WithOverwrite()); err != nil {
+		t.Fatal(err)
+	}
+	if got := *mock.getParams()[0].Value; got != "example.com" {
+		t.Errorf("Value = %q, want %q", got, "example.com")
+	}
+}
+
+func TestParamStore_WriteMap_invalidatesCache(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/host", "localhost"),
+	}}
+	cache := newTestCache()
+	ps, err := NewParamStore(WithClient(mock), WithSharedCache(cache, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Host string `ssm:"host"`
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{"host": "example.com"}
+	if err := ps.WriteMap(context.Background(), "", values, WithOverwrite()); err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Read(context.Background(), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "example.com" {
+		t.Errorf("Host = %q, want %q", cfg.Host, "example.com")
+	}
+}
+
+func TestParamStore_WriteMap_tags(t *testing.T) {
+	mock := &mockSSM{params: []types.Parameter{
+		stringParam("/dev/host", "localhost"),
+	}}
+	ps, err := NewParamStore(WithClient(mock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := map[string]string{"team": "payments", "environment": "dev"}
+	values := map[string]string{"host": "example.com", "port": "8080"}
+	if err := ps.WriteMap(context.Background(), "dev", values, WithOverwrite(), WithTags(tags)); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]string{
+		"/dev/host": tags,
+		"/dev/port": tags,
+	}
+	if diff := cmp.Diff(mock.tags, want); diff != "" {
+		t.Errorf("Tags (-got +want)\n%s", diff)
+	}
+
+	// New parameters are tagged when created
+	mock.tags = nil
+	if err := ps.WriteMap(context.Background(), "prod", values, WithTags(tags)); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(mock.tags); got != 2 {
+		t.Errorf("Tagged %d parameters, want 2", got)
+	}
+	if put := mock.puts[len(mock.puts)-1]; len(put.Tags) != 2 {
+		t.Errorf("PutParameter tags = %d, want 2", len(put.Tags))
+	}
+}